@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/slack-go/slack"
+)
+
+// BlockSpec is a simplified, JSON-friendly description of a single Block Kit
+// block. Redis producers send a list of these under SlackMessage.BlocksTemplate
+// instead of hand-crafting the raw Block Kit schema; buildBlocks compiles them
+// into real slack.Block values before the message is posted.
+type BlockSpec struct {
+	Type     string       `json:"type"`                // "header", "section", "divider", "context", "image", "actions"
+	Text     string       `json:"text,omitempty"`      // header/section/context text
+	Markdown bool         `json:"markdown,omitempty"`  // use mrkdwn instead of plain_text for Text
+	Fields   []string     `json:"fields,omitempty"`    // section fields, always rendered as mrkdwn
+	ImageURL string       `json:"image_url,omitempty"` // image block
+	AltText  string       `json:"alt_text,omitempty"`  // image block
+	Elements []string     `json:"elements,omitempty"`  // context block mrkdwn elements
+	Buttons  []ButtonSpec `json:"buttons,omitempty"`   // actions block
+}
+
+// ButtonSpec describes a single button element inside an actions BlockSpec.
+type ButtonSpec struct {
+	Text     string `json:"text"`
+	ActionID string `json:"action_id"`
+	Value    string `json:"value,omitempty"`
+	Style    string `json:"style,omitempty"` // "primary", "danger", or "" for default
+	URL      string `json:"url,omitempty"`
+}
+
+// textObject builds a Block Kit text object, choosing mrkdwn or plain_text.
+func textObject(text string, markdown bool) *slack.TextBlockObject {
+	if markdown {
+		return slack.NewTextBlockObject(slack.MarkdownType, text, false, false)
+	}
+	return slack.NewTextBlockObject(slack.PlainTextType, text, false, false)
+}
+
+// buildBlock compiles a single BlockSpec into a slack.Block, returning
+// ErrInvalidMessage wrapped with details if the spec is malformed.
+func buildBlock(spec BlockSpec) (slack.Block, error) {
+	switch spec.Type {
+	case "header":
+		if spec.Text == "" {
+			return nil, fmt.Errorf("%w: header block requires text", ErrInvalidMessage)
+		}
+		return slack.NewHeaderBlock(textObject(spec.Text, false)), nil
+
+	case "section":
+		var fields []*slack.TextBlockObject
+		for _, f := range spec.Fields {
+			fields = append(fields, textObject(f, true))
+		}
+		var text *slack.TextBlockObject
+		if spec.Text != "" {
+			text = textObject(spec.Text, true)
+		}
+		if text == nil && len(fields) == 0 {
+			return nil, fmt.Errorf("%w: section block requires text or fields", ErrInvalidMessage)
+		}
+		return slack.NewSectionBlock(text, fields, nil), nil
+
+	case "divider":
+		return slack.NewDividerBlock(), nil
+
+	case "context":
+		if len(spec.Elements) == 0 {
+			return nil, fmt.Errorf("%w: context block requires at least one element", ErrInvalidMessage)
+		}
+		var elements []slack.MixedElement
+		for _, e := range spec.Elements {
+			elements = append(elements, textObject(e, true))
+		}
+		return slack.NewContextBlock("", elements...), nil
+
+	case "image":
+		if spec.ImageURL == "" || spec.AltText == "" {
+			return nil, fmt.Errorf("%w: image block requires image_url and alt_text", ErrInvalidMessage)
+		}
+		return slack.NewImageBlock(spec.ImageURL, spec.AltText, "", nil), nil
+
+	case "actions":
+		if len(spec.Buttons) == 0 {
+			return nil, fmt.Errorf("%w: actions block requires at least one button", ErrInvalidMessage)
+		}
+		var elements []slack.BlockElement
+		for _, b := range spec.Buttons {
+			if b.ActionID == "" || b.Text == "" {
+				return nil, fmt.Errorf("%w: actions button requires action_id and text", ErrInvalidMessage)
+			}
+			btn := slack.NewButtonBlockElement(b.ActionID, b.Value, textObject(b.Text, false))
+			if b.Style != "" {
+				btn.Style = slack.Style(b.Style)
+			}
+			if b.URL != "" {
+				btn.URL = b.URL
+			}
+			elements = append(elements, btn)
+		}
+		return slack.NewActionBlock("", elements...), nil
+
+	default:
+		return nil, fmt.Errorf("%w: unknown block type %q", ErrInvalidMessage, spec.Type)
+	}
+}
+
+// buildBlocks compiles a list of BlockSpecs into a slack.Blocks value suitable
+// for slack.MsgOptionBlocks. It rejects the whole template with ErrInvalidMessage
+// if any single block is malformed, rather than forwarding a partial or broken
+// payload on to Slack.
+func buildBlocks(specs []BlockSpec) (slack.Blocks, error) {
+	blocks := make([]slack.Block, 0, len(specs))
+	for _, spec := range specs {
+		b, err := buildBlock(spec)
+		if err != nil {
+			return slack.Blocks{}, err
+		}
+		blocks = append(blocks, b)
+	}
+	return slack.Blocks{BlockSet: blocks}, nil
+}
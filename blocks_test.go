@@ -0,0 +1,54 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/slack-go/slack"
+)
+
+func TestBuildBlocks(t *testing.T) {
+	specs := []BlockSpec{
+		{Type: "header", Text: "Deploy finished"},
+		{Type: "section", Text: "*service*: checkout", Fields: []string{"*env*: prod", "*status*: ok"}},
+		{Type: "divider"},
+		{Type: "context", Elements: []string{"triggered by <@U123>"}},
+		{Type: "image", ImageURL: "https://example.com/graph.png", AltText: "graph"},
+		{Type: "actions", Buttons: []ButtonSpec{{Text: "Rollback", ActionID: "rollback", Style: "danger"}}},
+	}
+
+	blocks, err := buildBlocks(specs)
+	if err != nil {
+		t.Fatalf("buildBlocks() error = %v, want nil", err)
+	}
+	if len(blocks.BlockSet) != len(specs) {
+		t.Fatalf("len(blocks.BlockSet) = %d, want %d", len(blocks.BlockSet), len(specs))
+	}
+	if _, ok := blocks.BlockSet[0].(*slack.HeaderBlock); !ok {
+		t.Errorf("blocks.BlockSet[0] = %T, want *slack.HeaderBlock", blocks.BlockSet[0])
+	}
+}
+
+func TestBuildBlocksInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		specs []BlockSpec
+	}{
+		{name: "unknown type", specs: []BlockSpec{{Type: "carousel"}}},
+		{name: "header without text", specs: []BlockSpec{{Type: "header"}}},
+		{name: "section without text or fields", specs: []BlockSpec{{Type: "section"}}},
+		{name: "context without elements", specs: []BlockSpec{{Type: "context"}}},
+		{name: "image without alt text", specs: []BlockSpec{{Type: "image", ImageURL: "https://example.com/x.png"}}},
+		{name: "actions without buttons", specs: []BlockSpec{{Type: "actions"}}},
+		{name: "button without action_id", specs: []BlockSpec{{Type: "actions", Buttons: []ButtonSpec{{Text: "Go"}}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := buildBlocks(tt.specs)
+			if !errors.Is(err, ErrInvalidMessage) {
+				t.Errorf("buildBlocks() error = %v, want wrapped ErrInvalidMessage", err)
+			}
+		})
+	}
+}
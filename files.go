@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+// uploadSlackFile uploads a file to Slack and logs the outcome. It is the
+// fire-and-forget entry point used by the Redis-backed processFiles loop,
+// where there is no caller waiting on the result.
+func uploadSlackFile(ctx context.Context, slackClient *slack.Client, upload SlackFileUpload, rl *RateLimiter) {
+	if _, err := uploadSlackFileWithResponse(ctx, slackClient, upload, rl); err != nil {
+		slog.Error(fmt.Sprintf("Error uploading file: %v", err))
+	}
+}
+
+// uploadSlackFileWithResponse uploads a file to every channel in
+// upload.Channels and returns the last uploaded file's (id, permalink) pair so
+// HTTP callers can relay it back to the client. The permalink isn't present
+// on the slack.FileSummary UploadFileV2Context returns, so it's fetched with a
+// follow-up files.info call; a failure there is logged and leaves Permalink
+// empty rather than failing the whole upload. The file content is read from
+// upload.Content (base64) if set, otherwise fetched from upload.URL. rl
+// throttles the call to respect Slack's per-channel rate limits.
+func uploadSlackFileWithResponse(ctx context.Context, slackClient *slack.Client, upload SlackFileUpload, rl *RateLimiter) (FileResponse, error) {
+	if len(upload.Channels) == 0 || upload.Filename == "" {
+		return FileResponse{}, fmt.Errorf("%w: channels and filename are required", ErrInvalidMessage)
+	}
+	if upload.Content == "" && upload.URL == "" {
+		return FileResponse{}, fmt.Errorf("%w: either content or url is required", ErrInvalidMessage)
+	}
+
+	content, err := resolveFileContent(upload)
+	if err != nil {
+		return FileResponse{}, err
+	}
+
+	var result FileResponse
+	for _, channel := range upload.Channels {
+		if rl != nil {
+			if err := rl.Wait(ctx, "files.upload", channel); err != nil {
+				return FileResponse{}, fmt.Errorf("rate limit wait interrupted: %w", err)
+			}
+		}
+
+		apiStart := time.Now()
+		file, err := slackClient.UploadFileV2Context(ctx, slack.UploadFileV2Parameters{
+			Channel:         channel,
+			Reader:          bytes.NewReader(content),
+			FileSize:        len(content),
+			Filename:        upload.Filename,
+			SnippetType:     upload.Filetype,
+			Title:           upload.Title,
+			InitialComment:  upload.InitialComment,
+			ThreadTimestamp: upload.ThreadTS,
+		})
+		slackAPILatencySeconds.WithLabelValues("files.upload").Observe(time.Since(apiStart).Seconds())
+		if err != nil {
+			if rl != nil {
+				rl.throttleIfRateLimited("files.upload", channel, err)
+			}
+			return FileResponse{}, fmt.Errorf("error uploading file to Slack: %w", err)
+		}
+
+		result = FileResponse{FileID: file.ID, Permalink: fetchFilePermalink(ctx, slackClient, file.ID)}
+		slog.Info(fmt.Sprintf("File '%s' uploaded successfully to channel %s (id: %s)", upload.Filename, channel, file.ID))
+	}
+
+	return result, nil
+}
+
+// fetchFilePermalink looks up fileID's permalink via files.info. It logs and
+// returns an empty string on failure rather than erroring, since the upload
+// itself already succeeded by this point.
+func fetchFilePermalink(ctx context.Context, slackClient *slack.Client, fileID string) string {
+	file, _, _, err := slackClient.GetFileInfoContext(ctx, fileID, 0, 0)
+	if err != nil {
+		slog.Error(fmt.Sprintf("Error fetching permalink for file '%s': %v", fileID, err))
+		return ""
+	}
+	return file.Permalink
+}
+
+// resolveFileContent returns the raw file bytes for upload, decoding
+// upload.Content as base64 if set, otherwise fetching upload.URL.
+func resolveFileContent(upload SlackFileUpload) ([]byte, error) {
+	if upload.Content != "" {
+		content, err := base64.StdEncoding.DecodeString(upload.Content)
+		if err != nil {
+			return nil, fmt.Errorf("%w: content is not valid base64: %v", ErrInvalidMessage, err)
+		}
+		return content, nil
+	}
+
+	resp, err := http.Get(upload.URL)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching file from url: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error fetching file from url: unexpected status %d", resp.StatusCode)
+	}
+
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading file from url: %w", err)
+	}
+	return content, nil
+}
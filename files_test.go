@@ -0,0 +1,67 @@
+package main
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestResolveFileContentBase64(t *testing.T) {
+	want := []byte("hello world")
+	upload := SlackFileUpload{Content: base64.StdEncoding.EncodeToString(want)}
+
+	got, err := resolveFileContent(upload)
+	if err != nil {
+		t.Fatalf("resolveFileContent() error = %v", err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("resolveFileContent() = %q, want %q", got, want)
+	}
+}
+
+func TestResolveFileContentInvalidBase64(t *testing.T) {
+	upload := SlackFileUpload{Content: "not-valid-base64!!"}
+
+	if _, err := resolveFileContent(upload); !errors.Is(err, ErrInvalidMessage) {
+		t.Errorf("resolveFileContent() error = %v, want wrapped ErrInvalidMessage", err)
+	}
+}
+
+func TestResolveFileContentURL(t *testing.T) {
+	want := []byte("file from url")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(want)
+	}))
+	defer server.Close()
+
+	upload := SlackFileUpload{URL: server.URL}
+	got, err := resolveFileContent(upload)
+	if err != nil {
+		t.Fatalf("resolveFileContent() error = %v", err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("resolveFileContent() = %q, want %q", got, want)
+	}
+}
+
+func TestUploadSlackFileWithResponseValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		upload SlackFileUpload
+	}{
+		{name: "missing channels", upload: SlackFileUpload{Filename: "a.txt", Content: "aGk="}},
+		{name: "missing filename", upload: SlackFileUpload{Channels: []string{"C1"}, Content: "aGk="}},
+		{name: "missing content and url", upload: SlackFileUpload{Channels: []string{"C1"}, Filename: "a.txt"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := uploadSlackFileWithResponse(nil, nil, tt.upload, nil)
+			if !errors.Is(err, ErrInvalidMessage) {
+				t.Errorf("uploadSlackFileWithResponse() error = %v, want wrapped ErrInvalidMessage", err)
+			}
+		})
+	}
+}
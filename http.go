@@ -2,21 +2,42 @@ package main
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
-	"log"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
+	"strings"
 
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/redis/go-redis/v9"
 	"github.com/slack-go/slack"
 )
 
 // startHTTPServer starts the HTTP server for posting messages via HTTP endpoint
-func startHTTPServer(ctx context.Context, addr string, slackClient *slack.Client, rdb *redis.Client, timeBombChannel string) *http.Server {
+func startHTTPServer(ctx context.Context, addr string, slackClient *slack.Client, rdb *redis.Client, timeBombChannel, deadLetterChannel string, signingSecret string, interactionsListKey string, commandsListKey string, rl *RateLimiter, ts *TemplateStore) *http.Server {
 	mux := http.NewServeMux()
 
 	// POST /message endpoint
 	mux.HandleFunc("/message", func(w http.ResponseWriter, r *http.Request) {
-		handlePostMessage(ctx, w, r, slackClient, rdb, timeBombChannel)
+		handlePostMessage(ctx, w, r, slackClient, rdb, timeBombChannel, deadLetterChannel, rl, ts)
+	})
+
+	// POST /file endpoint
+	mux.HandleFunc("/file", func(w http.ResponseWriter, r *http.Request) {
+		handlePostFile(ctx, w, r, slackClient, rl)
+	})
+
+	// POST /slack/interactions endpoint: buttons, select menus, modals
+	mux.HandleFunc("/slack/interactions", func(w http.ResponseWriter, r *http.Request) {
+		handleInteractions(ctx, w, r, rdb, signingSecret, interactionsListKey)
+	})
+
+	// POST /slack/commands endpoint: slash commands
+	mux.HandleFunc("/slack/commands", func(w http.ResponseWriter, r *http.Request) {
+		handleCommands(ctx, w, r, rdb, signingSecret, commandsListKey)
 	})
 
 	// Health check endpoint
@@ -25,6 +46,9 @@ func startHTTPServer(ctx context.Context, addr string, slackClient *slack.Client
 		w.Write([]byte("OK"))
 	})
 
+	// Prometheus metrics endpoint
+	mux.Handle("/metrics", promhttp.Handler())
+
 	server := &http.Server{
 		Addr:    addr,
 		Handler: mux,
@@ -32,18 +56,18 @@ func startHTTPServer(ctx context.Context, addr string, slackClient *slack.Client
 
 	// Start server in a goroutine
 	go func() {
-		log.Printf("Starting HTTP server on %s", addr)
+		slog.Info(fmt.Sprintf("Starting HTTP server on %s", addr))
 		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Printf("HTTP server error: %v", err)
+			slog.Error(fmt.Sprintf("HTTP server error: %v", err))
 		}
 	}()
 
 	// Handle graceful shutdown
 	go func() {
 		<-ctx.Done()
-		log.Println("Shutting down HTTP server...")
+		slog.Info("Shutting down HTTP server...")
 		if err := server.Shutdown(context.Background()); err != nil {
-			log.Printf("Error shutting down HTTP server: %v", err)
+			slog.Error(fmt.Sprintf("Error shutting down HTTP server: %v", err))
 		}
 	}()
 
@@ -51,7 +75,11 @@ func startHTTPServer(ctx context.Context, addr string, slackClient *slack.Client
 }
 
 // handlePostMessage handles POST requests to send Slack messages
-func handlePostMessage(ctx context.Context, w http.ResponseWriter, r *http.Request, slackClient *slack.Client, rdb *redis.Client, timeBombChannel string) {
+func handlePostMessage(ctx context.Context, w http.ResponseWriter, r *http.Request, slackClient *slack.Client, rdb *redis.Client, timeBombChannel, deadLetterChannel string, rl *RateLimiter, ts *TemplateStore) {
+	requestID := requestIDFromHeader(r)
+	logger := slog.With("request_id", requestID)
+	w.Header().Set("X-Request-Id", requestID)
+
 	// Only allow POST requests
 	if r.Method != http.MethodPost {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -61,15 +89,15 @@ func handlePostMessage(ctx context.Context, w http.ResponseWriter, r *http.Reque
 	// Parse request body
 	var msg SlackMessage
 	if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
-		log.Printf("Error parsing request body: %v", err)
+		logger.Error("Error parsing request body", "error", err)
 		http.Error(w, "Invalid JSON payload", http.StatusBadRequest)
 		return
 	}
 
 	// Send message to Slack
-	channelID, timestamp, err := sendSlackMessageWithResponse(ctx, slackClient, rdb, msg, timeBombChannel)
+	channelID, timestamp, err := sendSlackMessageWithResponse(ctx, slackClient, rdb, msg, timeBombChannel, deadLetterChannel, rl, ts)
 	if err != nil {
-		log.Printf("Error sending message to Slack: %v", err)
+		logger.Error("Error sending message to Slack", "channel", msg.Channel, "error", err)
 		if err == ErrInvalidMessage || err == ErrInvalidTTL {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 		} else {
@@ -77,6 +105,7 @@ func handlePostMessage(ctx context.Context, w http.ResponseWriter, r *http.Reque
 		}
 		return
 	}
+	logger.Info("Message sent", "channel", channelID, "ts", timestamp)
 
 	// Build and send response
 	response := MessageResponse{
@@ -87,6 +116,97 @@ func handlePostMessage(ctx context.Context, w http.ResponseWriter, r *http.Reque
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	if err := json.NewEncoder(w).Encode(response); err != nil {
-		log.Printf("Error encoding response: %v", err)
+		logger.Error("Error encoding response", "error", err)
+	}
+}
+
+// handlePostFile handles POST requests to upload a file to Slack via
+// multipart/form-data. Besides the uploaded file part (field "file"), it
+// accepts the same metadata fields as SlackFileUpload: "channels" (comma
+// separated), "filename", "title", "initial_comment", and "thread_ts".
+func handlePostFile(ctx context.Context, w http.ResponseWriter, r *http.Request, slackClient *slack.Client, rl *RateLimiter) {
+	requestID := requestIDFromHeader(r)
+	logger := slog.With("request_id", requestID)
+	w.Header().Set("X-Request-Id", requestID)
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		logger.Error("Error parsing multipart form", "error", err)
+		http.Error(w, "Invalid multipart form", http.StatusBadRequest)
+		return
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		logger.Error("Error reading uploaded file", "error", err)
+		http.Error(w, "Missing or invalid 'file' part", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		logger.Error("Error reading uploaded file content", "error", err)
+		http.Error(w, "Error reading uploaded file", http.StatusInternalServerError)
+		return
+	}
+
+	filename := r.FormValue("filename")
+	if filename == "" {
+		filename = header.Filename
+	}
+
+	upload := SlackFileUpload{
+		Channels:       splitChannels(r.FormValue("channels")),
+		Filename:       filename,
+		Title:          r.FormValue("title"),
+		InitialComment: r.FormValue("initial_comment"),
+		ThreadTS:       r.FormValue("thread_ts"),
+		Content:        base64.StdEncoding.EncodeToString(content),
+	}
+
+	response, err := uploadSlackFileWithResponse(ctx, slackClient, upload, rl)
+	if err != nil {
+		logger.Error("Error uploading file to Slack", "channels", upload.Channels, "error", err)
+		if errors.Is(err, ErrInvalidMessage) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		} else {
+			http.Error(w, "Failed to upload file to Slack", http.StatusInternalServerError)
+		}
+		return
+	}
+	logger.Info("File uploaded", "channels", upload.Channels, "file_id", response.FileID)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Error("Error encoding response", "error", err)
+	}
+}
+
+// splitChannels splits a comma-separated "channels" form value into a
+// trimmed, non-empty slice.
+func splitChannels(value string) []string {
+	var channels []string
+	for _, channel := range strings.Split(value, ",") {
+		channel = strings.TrimSpace(channel)
+		if channel != "" {
+			channels = append(channels, channel)
+		}
+	}
+	return channels
+}
+
+// requestIDFromHeader returns the caller-supplied X-Request-Id if present, or
+// generates a new random one, so every handlePostMessage call can be traced
+// through the structured logs it emits.
+func requestIDFromHeader(r *http.Request) string {
+	if id := r.Header.Get("X-Request-Id"); id != "" {
+		return id
 	}
+	return newRequestID()
 }
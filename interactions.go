@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/slack-go/slack"
+)
+
+// verifySlackSignature checks the X-Slack-Signature / X-Slack-Request-Timestamp
+// headers against signingSecret using the same HMAC-SHA256, constant-time,
+// 5-minute-window scheme the Slack API docs describe. It rejects requests whose
+// timestamp is more than 5 minutes old. An empty signingSecret is always
+// rejected rather than verified against an empty-key HMAC, since main refusing
+// to start without one is the only thing standing between that and silently
+// accepting forged requests.
+func verifySlackSignature(signingSecret string, header http.Header, body []byte) error {
+	if signingSecret == "" {
+		return ErrSigningSecretNotSet
+	}
+
+	verifier, err := slack.NewSecretsVerifier(header, signingSecret)
+	if err != nil {
+		return err
+	}
+	if _, err := verifier.Write(body); err != nil {
+		return err
+	}
+	return verifier.Ensure()
+}
+
+// handleInteractions handles POST /slack/interactions: Slack's interactive
+// component callbacks (buttons, select menus, modals), delivered as a
+// url-encoded `payload=` form body. Validated callbacks are RPUSH'd as JSON
+// onto listKey for downstream workers to consume.
+func handleInteractions(ctx context.Context, w http.ResponseWriter, r *http.Request, rdb *redis.Client, signingSecret, listKey string) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		slog.Error(fmt.Sprintf("Error reading interaction request body: %v", err))
+		http.Error(w, "Error reading request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := verifySlackSignature(signingSecret, r.Header, body); err != nil {
+		slog.Error(fmt.Sprintf("Rejecting interaction: %v", err))
+		http.Error(w, "Invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	values, err := url.ParseQuery(string(body))
+	if err != nil {
+		slog.Error(fmt.Sprintf("Error parsing interaction form body: %v", err))
+		http.Error(w, "Invalid form body", http.StatusBadRequest)
+		return
+	}
+
+	var callback slack.InteractionCallback
+	if err := json.Unmarshal([]byte(values.Get("payload")), &callback); err != nil {
+		slog.Error(fmt.Sprintf("Error parsing interaction payload: %v", err))
+		http.Error(w, "Invalid interaction payload", http.StatusBadRequest)
+		return
+	}
+
+	payload, err := json.Marshal(callback)
+	if err != nil {
+		slog.Error(fmt.Sprintf("Error marshaling interaction callback: %v", err))
+		http.Error(w, "Internal error", http.StatusInternalServerError)
+		return
+	}
+
+	if err := rdb.RPush(ctx, listKey, string(payload)).Err(); err != nil {
+		slog.Error(fmt.Sprintf("Error pushing interaction to Redis list '%s': %v", listKey, err))
+		http.Error(w, "Failed to queue interaction", http.StatusInternalServerError)
+		return
+	}
+
+	slog.Info(fmt.Sprintf("Queued interaction type '%s' to Redis list '%s'", callback.Type, listKey))
+	w.WriteHeader(http.StatusOK)
+}
+
+// handleCommands handles POST /slack/commands: Slack slash command invocations,
+// delivered as a standard application/x-www-form-urlencoded body. Validated
+// commands are RPUSH'd as JSON onto listKey for downstream workers to consume.
+func handleCommands(ctx context.Context, w http.ResponseWriter, r *http.Request, rdb *redis.Client, signingSecret, listKey string) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		slog.Error(fmt.Sprintf("Error reading slash command request body: %v", err))
+		http.Error(w, "Error reading request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := verifySlackSignature(signingSecret, r.Header, body); err != nil {
+		slog.Error(fmt.Sprintf("Rejecting slash command: %v", err))
+		http.Error(w, "Invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	// slack.SlashCommandParse reads the body itself via r.ParseForm, so restore
+	// it after consuming it above for signature verification.
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	cmd, err := slack.SlashCommandParse(r)
+	if err != nil {
+		slog.Error(fmt.Sprintf("Error parsing slash command: %v", err))
+		http.Error(w, "Invalid slash command payload", http.StatusBadRequest)
+		return
+	}
+
+	payload, err := json.Marshal(cmd)
+	if err != nil {
+		slog.Error(fmt.Sprintf("Error marshaling slash command: %v", err))
+		http.Error(w, "Internal error", http.StatusInternalServerError)
+		return
+	}
+
+	if err := rdb.RPush(ctx, listKey, string(payload)).Err(); err != nil {
+		slog.Error(fmt.Sprintf("Error pushing slash command to Redis list '%s': %v", listKey, err))
+		http.Error(w, "Failed to queue command", http.StatusInternalServerError)
+		return
+	}
+
+	slog.Info(fmt.Sprintf("Queued slash command '%s' to Redis list '%s'", cmd.Command, listKey))
+	w.WriteHeader(http.StatusOK)
+}
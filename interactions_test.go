@@ -0,0 +1,56 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func signBody(secret string, timestamp int64, body []byte) string {
+	base := fmt.Sprintf("v0:%d:%s", timestamp, body)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(base))
+	return "v0=" + fmt.Sprintf("%x", mac.Sum(nil))
+}
+
+func TestVerifySlackSignature(t *testing.T) {
+	const secret = "shhh-its-a-secret"
+	body := []byte("payload=%7B%22type%22%3A%22block_actions%22%7D")
+
+	t.Run("valid signature", func(t *testing.T) {
+		ts := time.Now().Unix()
+		header := http.Header{}
+		header.Set("X-Slack-Request-Timestamp", strconv.FormatInt(ts, 10))
+		header.Set("X-Slack-Signature", signBody(secret, ts, body))
+
+		if err := verifySlackSignature(secret, header, body); err != nil {
+			t.Errorf("verifySlackSignature() error = %v, want nil", err)
+		}
+	})
+
+	t.Run("wrong secret", func(t *testing.T) {
+		ts := time.Now().Unix()
+		header := http.Header{}
+		header.Set("X-Slack-Request-Timestamp", strconv.FormatInt(ts, 10))
+		header.Set("X-Slack-Signature", signBody("wrong-secret", ts, body))
+
+		if err := verifySlackSignature(secret, header, body); err == nil {
+			t.Error("verifySlackSignature() error = nil, want error for mismatched secret")
+		}
+	})
+
+	t.Run("stale timestamp", func(t *testing.T) {
+		ts := time.Now().Add(-10 * time.Minute).Unix()
+		header := http.Header{}
+		header.Set("X-Slack-Request-Timestamp", strconv.FormatInt(ts, 10))
+		header.Set("X-Slack-Signature", signBody(secret, ts, body))
+
+		if err := verifySlackSignature(secret, header, body); err == nil {
+			t.Error("verifySlackSignature() error = nil, want error for stale timestamp")
+		}
+	})
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewRequestID(t *testing.T) {
+	a := newRequestID()
+	b := newRequestID()
+
+	if a == "" {
+		t.Fatal("newRequestID() returned an empty string")
+	}
+	if a == b {
+		t.Errorf("newRequestID() returned the same value twice: %q", a)
+	}
+}
+
+func TestRequestIDFromHeader(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+	}{
+		{
+			name:   "caller-supplied request id is preserved",
+			header: "abc123",
+		},
+		{
+			name:   "missing request id is generated",
+			header: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/slack/message", nil)
+			if tt.header != "" {
+				req.Header.Set("X-Request-Id", tt.header)
+			}
+
+			got := requestIDFromHeader(req)
+			if got == "" {
+				t.Fatal("requestIDFromHeader() returned an empty string")
+			}
+			if tt.header != "" && got != tt.header {
+				t.Errorf("requestIDFromHeader() = %v, want %v", got, tt.header)
+			}
+		})
+	}
+}
@@ -2,9 +2,13 @@ package main
 
 import (
 	"context"
-	"log"
+	"encoding/json"
+	"fmt"
+	"log/slog"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
@@ -13,22 +17,53 @@ import (
 )
 
 func main() {
+	slog.SetDefault(slog.New(slog.NewJSONHandler(os.Stdout, nil)))
+
 	// Load configuration from environment variables
 	redisAddr := getEnv("REDIS_ADDR", "localhost:6379")
 	redisPassword := getEnv("REDIS_PASSWORD", "")
 	redisDB := 0 // Using default DB
 	redisListKey := getEnv("REDIS_LIST_KEY", "slack_messages")
 	redisReactionListKey := getEnv("REDIS_REACTION_LIST_KEY", "slack_reactions")
+	redisFilesListKey := getEnv("REDIS_FILES_LIST_KEY", "slack_files")
+	redisUpdatesListKey := getEnv("REDIS_UPDATES_LIST_KEY", "slack_updates")
+	redisDeletesListKey := getEnv("REDIS_DELETES_LIST_KEY", "slack_deletes")
 	timeBombChannel := getEnv("TIMEBOMB_REDIS_CHANNEL", "timebomb-messages")
 	slackToken := getEnv("SLACK_BOT_TOKEN", "")
+	slackSigningSecret := getEnv("SLACK_SIGNING_SECRET", "")
+	redisInteractionsListKey := getEnv("REDIS_INTERACTIONS_LIST_KEY", "slack_interactions")
+	redisCommandsListKey := getEnv("REDIS_COMMANDS_LIST_KEY", "slack_commands")
 	httpAddr := getEnv("HTTP_ADDR", ":8080")
+	slackAppToken := getEnv("SLACK_APP_TOKEN", "")
+	redisEventsListPrefix := getEnv("REDIS_EVENTS_LIST_PREFIX", "slack_events:")
+	socketModeEventAllowlist := parseAllowlist(getEnv("SOCKET_MODE_EVENT_ALLOWLIST", ""))
+	socketModeChannelAllowlist := parseAllowlist(getEnv("SOCKET_MODE_CHANNEL_ALLOWLIST", ""))
+	redisMode := getEnv("REDIS_MODE", "list")
+	redisConsumerGroup := getEnv("REDIS_CONSUMER_GROUP", "slackliner")
+	redisConsumerName := getEnv("REDIS_CONSUMER_NAME", "slackliner-1")
+	redisDeadLetterStreamKey := getEnv("REDIS_DEAD_LETTER_STREAM_KEY", "slack_dead_letter")
+	deadLetterChannel := getEnv("DEAD_LETTER_REDIS_CHANNEL", "slack_dead_letter_messages")
+	maxRetries := getEnvInt("MAX_RETRIES", 5)
+	rateLimitBurst := getEnvFloat("RATE_LIMIT_BURST", 1)
+	rateLimitDefaultPerSec := getEnvFloat("RATE_LIMIT_DEFAULT_PER_SEC", 1)
+	rateLimiter := NewRateLimiter(map[string]float64{
+		"chat.postMessage": getEnvFloat("RATE_LIMIT_CHAT_POST_PER_SEC", 1),
+		"reactions.add":    getEnvFloat("RATE_LIMIT_REACTIONS_ADD_PER_SEC", 1),
+	}, rateLimitDefaultPerSec, rateLimitBurst)
+	templateDir := getEnv("TEMPLATE_DIR", "")
+	templateHashKey := getEnv("TEMPLATE_REDIS_HASH", "slackliner:templates")
 
 	if slackToken == "" {
-		log.Fatal("SLACK_BOT_TOKEN environment variable is required")
+		slog.Error("SLACK_BOT_TOKEN environment variable is required")
+		os.Exit(1)
+	}
+	if slackSigningSecret == "" {
+		slog.Error("SLACK_SIGNING_SECRET environment variable is required to verify /slack/interactions and /slack/commands requests")
+		os.Exit(1)
 	}
 
 	// Initialize Redis client
-	log.Printf("Connecting to Redis at %s...", redisAddr)
+	slog.Info(fmt.Sprintf("Connecting to Redis at %s...", redisAddr))
 	rdb := redis.NewClient(&redis.Options{
 		Addr:     redisAddr,
 		Password: redisPassword,
@@ -39,19 +74,32 @@ func main() {
 	// Test Redis connection
 	ctx := context.Background()
 	if err := rdb.Ping(ctx).Err(); err != nil {
-		log.Fatalf("Failed to connect to Redis: %v", err)
+		slog.Error(fmt.Sprintf("Failed to connect to Redis: %v", err))
+		os.Exit(1)
 	}
-	log.Println("Connected to Redis successfully")
+	slog.Info("Connected to Redis successfully")
 
 	// Initialize Slack client
-	log.Println("Initializing Slack client...")
+	slog.Info("Initializing Slack client...")
 	slackClient := slack.New(slackToken)
 
 	// Test Slack connection
 	if _, err := slackClient.AuthTest(); err != nil {
-		log.Fatalf("Failed to authenticate with Slack: %v", err)
+		slog.Error(fmt.Sprintf("Failed to authenticate with Slack: %v", err))
+		os.Exit(1)
+	}
+	slog.Info("Slack authentication successful")
+
+	// Load message templates, if configured
+	templateStore := NewTemplateStore()
+	if templateDir != "" {
+		if err := templateStore.LoadDir(templateDir); err != nil {
+			slog.Error(fmt.Sprintf("Error loading templates from dir '%s': %v", templateDir, err))
+		}
+	}
+	if err := templateStore.LoadRedis(ctx, rdb, templateHashKey); err != nil {
+		slog.Error(fmt.Sprintf("Error loading templates from Redis hash '%s': %v", templateHashKey, err))
 	}
-	log.Println("Slack authentication successful")
 
 	// Setup graceful shutdown with context
 	ctx, cancel := context.WithCancel(ctx)
@@ -60,20 +108,78 @@ func main() {
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 
+	// Reload templates on SIGHUP without restarting
+	go watchSIGHUP(ctx, templateStore, rdb, templateDir, templateHashKey)
+
 	// Start HTTP server
-	startHTTPServer(ctx, httpAddr, slackClient, rdb, timeBombChannel)
+	startHTTPServer(ctx, httpAddr, slackClient, rdb, timeBombChannel, deadLetterChannel, slackSigningSecret, redisInteractionsListKey, redisCommandsListKey, rateLimiter, templateStore)
+
+	if redisMode == "stream" {
+		streamCfg := StreamConfig{
+			Group:         redisConsumerGroup,
+			Consumer:      redisConsumerName,
+			MaxRetries:    maxRetries,
+			DeadLetterKey: redisDeadLetterStreamKey,
+			ClaimMinIdle:  1 * time.Minute,
+			ClaimInterval: 30 * time.Second,
+		}
 
-	// Start message processing loop
-	log.Printf("Starting to listen for messages on Redis list '%s'...", redisListKey)
-	go processMessages(ctx, rdb, slackClient, redisListKey, timeBombChannel)
+		// Start message processing via Redis Streams (reliable, at-least-once delivery)
+		slog.Info(fmt.Sprintf("Starting to listen for messages on Redis stream '%s' (group '%s')...", redisListKey, redisConsumerGroup))
+		go processStream(ctx, rdb, redisListKey, streamCfg, func(payload []byte) error {
+			var msg SlackMessage
+			if err := json.Unmarshal(payload, &msg); err != nil {
+				return err
+			}
+			_, _, err := sendSlackMessageWithResponse(ctx, slackClient, rdb, msg, timeBombChannel, deadLetterChannel, rateLimiter, templateStore)
+			return err
+		})
 
-	// Start reaction processing loop
-	log.Printf("Starting to listen for reactions on Redis list '%s'...", redisReactionListKey)
-	go processReactions(ctx, rdb, slackClient, redisReactionListKey)
+		// Start reaction processing via Redis Streams
+		slog.Info(fmt.Sprintf("Starting to listen for reactions on Redis stream '%s' (group '%s')...", redisReactionListKey, redisConsumerGroup))
+		go processStream(ctx, rdb, redisReactionListKey, streamCfg, func(payload []byte) error {
+			var msg ReactionMessage
+			if err := json.Unmarshal(payload, &msg); err != nil {
+				return err
+			}
+			return addSlackReactionWithResponse(ctx, slackClient, rdb, msg, deadLetterChannel, rateLimiter)
+		})
+	} else {
+		// Start message processing loop
+		slog.Info(fmt.Sprintf("Starting to listen for messages on Redis list '%s'...", redisListKey))
+		go processMessages(ctx, rdb, slackClient, redisListKey, timeBombChannel, deadLetterChannel, rateLimiter, templateStore)
+
+		// Start reaction processing loop
+		slog.Info(fmt.Sprintf("Starting to listen for reactions on Redis list '%s'...", redisReactionListKey))
+		go processReactions(ctx, rdb, slackClient, redisReactionListKey, deadLetterChannel, rateLimiter)
+	}
+
+	// Start file upload processing loop
+	slog.Info(fmt.Sprintf("Starting to listen for file uploads on Redis list '%s'...", redisFilesListKey))
+	go processFiles(ctx, rdb, slackClient, redisFilesListKey, rateLimiter)
+
+	// Start message update/delete processing loops
+	slog.Info(fmt.Sprintf("Starting to listen for message updates on Redis list '%s'...", redisUpdatesListKey))
+	go processUpdates(ctx, rdb, slackClient, redisUpdatesListKey, rateLimiter)
+	slog.Info(fmt.Sprintf("Starting to listen for message deletes on Redis list '%s'...", redisDeletesListKey))
+	go processDeletes(ctx, rdb, slackClient, redisDeletesListKey, rateLimiter)
+
+	// Sample queue depth periodically so operators can see backlog building up
+	go sampleQueueDepth(ctx, rdb, []string{redisListKey, redisReactionListKey, redisFilesListKey, redisUpdatesListKey, redisDeletesListKey}, 15*time.Second)
+
+	// Start Socket Mode event ingestion, if an app-level token is configured
+	if slackAppToken != "" {
+		slog.Info("Starting Socket Mode event ingestion...")
+		go processSocketMode(ctx, rdb, slackAppToken, slackToken, SocketModeConfig{
+			EventTypeAllowlist: socketModeEventAllowlist,
+			ChannelAllowlist:   socketModeChannelAllowlist,
+			ListKeyPrefix:      redisEventsListPrefix,
+		})
+	}
 
 	// Wait for shutdown signal
 	<-sigChan
-	log.Println("Shutting down gracefully...")
+	slog.Info("Shutting down gracefully...")
 	cancel()
 	time.Sleep(1 * time.Second) // Give goroutines time to finish current operation
 }
@@ -84,3 +190,46 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// getEnvInt reads an integer env var, falling back to defaultValue if it is
+// unset or unparseable.
+func getEnvInt(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		slog.Info(fmt.Sprintf("Invalid integer value for %s=%q, using default %d", key, value, defaultValue))
+		return defaultValue
+	}
+	return parsed
+}
+
+// getEnvFloat reads a float env var, falling back to defaultValue if it is
+// unset or unparseable.
+func getEnvFloat(key string, defaultValue float64) float64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		slog.Info(fmt.Sprintf("Invalid float value for %s=%q, using default %v", key, value, defaultValue))
+		return defaultValue
+	}
+	return parsed
+}
+
+// parseAllowlist splits a comma-separated env value into a set. An empty
+// string yields an empty (non-restrictive) set.
+func parseAllowlist(value string) map[string]bool {
+	set := make(map[string]bool)
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			set[item] = true
+		}
+	}
+	return set
+}
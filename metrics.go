@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	messagesPostedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "slackliner_messages_posted_total",
+		Help: "Count of messages posted to Slack, labeled by channel and result.",
+	}, []string{"channel", "result"})
+
+	reactionsAddedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "slackliner_reactions_added_total",
+		Help: "Count of reactions added to Slack messages, labeled by channel and result.",
+	}, []string{"channel", "result"})
+
+	redisWaitSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "slackliner_redis_blpop_wait_seconds",
+		Help: "Time spent blocked in BLPOP, labeled by Redis list.",
+	}, []string{"list"})
+
+	slackAPILatencySeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "slackliner_slack_api_latency_seconds",
+		Help: "Slack API call latency, labeled by method.",
+	}, []string{"method"})
+
+	throttleEventsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "slackliner_rate_limit_throttle_events_total",
+		Help: "Count of 429 throttle events, labeled by Slack method.",
+	}, []string{"method"})
+
+	timeBombDispatchedTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "slackliner_timebomb_dispatched_total",
+		Help: "Count of messages published to TimeBomb for scheduled deletion.",
+	})
+
+	queueDepth = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "slackliner_queue_depth",
+		Help: "Sampled LLEN of a Redis list, labeled by list name.",
+	}, []string{"list"})
+)
+
+// sampleQueueDepth periodically samples LLEN for each configured Redis list
+// and publishes it as a gauge, so operators can see backlog building up.
+func sampleQueueDepth(ctx context.Context, rdb *redis.Client, listKeys []string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, key := range listKeys {
+				length, err := rdb.LLen(ctx, key).Result()
+				if err != nil {
+					continue
+				}
+				queueDepth.WithLabelValues(key).Set(float64(length))
+			}
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+// tokenBucket is a minimal per-key token bucket: it refills continuously at
+// ratePerSec up to a maximum of burst tokens, and can be paused for a fixed
+// duration (used when Slack returns a 429 with Retry-After).
+type tokenBucket struct {
+	mu          sync.Mutex
+	tokens      float64
+	ratePerSec  float64
+	burst       float64
+	lastRefill  time.Time
+	pausedUntil time.Time
+}
+
+func newTokenBucket(ratePerSec, burst float64) *tokenBucket {
+	return &tokenBucket{tokens: burst, ratePerSec: ratePerSec, burst: burst, lastRefill: time.Now()}
+}
+
+func (b *tokenBucket) refill() {
+	now := time.Now()
+	elapsed := now.Sub(b.lastRefill).Seconds()
+	b.tokens = math.Min(b.burst, b.tokens+elapsed*b.ratePerSec)
+	b.lastRefill = now
+}
+
+// wait blocks, respecting ctx, until a token is available and any active 429
+// pause has elapsed, then consumes one token.
+func (b *tokenBucket) wait(ctx context.Context) error {
+	for {
+		b.mu.Lock()
+		b.refill()
+
+		if until := b.pausedUntil; time.Now().Before(until) {
+			b.mu.Unlock()
+			select {
+			case <-time.After(time.Until(until)):
+				continue
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		if b.tokens >= 1 {
+			b.tokens--
+			b.mu.Unlock()
+			return nil
+		}
+
+		deficit := 1 - b.tokens
+		wait := time.Duration(deficit / b.ratePerSec * float64(time.Second))
+		b.mu.Unlock()
+
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// pause suspends the bucket for d, extending any existing pause rather than
+// shortening it.
+func (b *tokenBucket) pause(d time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if until := time.Now().Add(d); until.After(b.pausedUntil) {
+		b.pausedUntil = until
+	}
+}
+
+// RateLimiter maintains a token bucket per (method, channel) pair so that
+// heavy Redis producers can't hammer Slack past its per-method tier limits.
+// Buckets are created lazily with the default rate configured for their
+// method.
+type RateLimiter struct {
+	mu          sync.Mutex
+	buckets     map[string]*tokenBucket
+	ratesPerSec map[string]float64
+	defaultRate float64
+	burst       float64
+}
+
+// NewRateLimiter builds a RateLimiter. ratesPerSec maps Slack method names
+// (e.g. "chat.postMessage") to their allowed requests/sec per channel; methods
+// not present fall back to defaultRate. burst caps how many requests can be
+// made back-to-back before the bucket starts throttling.
+func NewRateLimiter(ratesPerSec map[string]float64, defaultRate, burst float64) *RateLimiter {
+	return &RateLimiter{
+		buckets:     make(map[string]*tokenBucket),
+		ratesPerSec: ratesPerSec,
+		defaultRate: defaultRate,
+		burst:       burst,
+	}
+}
+
+func (rl *RateLimiter) bucketFor(method, channel string) *tokenBucket {
+	key := method + ":" + channel
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	b, ok := rl.buckets[key]
+	if !ok {
+		rate := rl.defaultRate
+		if r, ok := rl.ratesPerSec[method]; ok {
+			rate = r
+		}
+		b = newTokenBucket(rate, rl.burst)
+		rl.buckets[key] = b
+	}
+	return b
+}
+
+// Wait blocks until method is allowed to run against channel according to its
+// token bucket.
+func (rl *RateLimiter) Wait(ctx context.Context, method, channel string) error {
+	return rl.bucketFor(method, channel).wait(ctx)
+}
+
+// Throttle pauses method's bucket for channel for d and logs that throttling
+// occurred. Call this after receiving a 429 so subsequent calls back off.
+func (rl *RateLimiter) Throttle(method, channel string, d time.Duration) {
+	rl.bucketFor(method, channel).pause(d)
+	throttleEventsTotal.WithLabelValues(method).Inc()
+	slog.Info(fmt.Sprintf("Rate limit: throttling %s on channel %s for %s", method, channel, d))
+}
+
+// throttleIfRateLimited inspects err for a Slack 429 response and, if found,
+// pauses method's bucket for channel for the server-suggested Retry-After.
+func (rl *RateLimiter) throttleIfRateLimited(method, channel string, err error) {
+	var rateLimitedErr *slack.RateLimitedError
+	if errors.As(err, &rateLimitedErr) {
+		rl.Throttle(method, channel, rateLimitedErr.RetryAfter)
+	}
+}
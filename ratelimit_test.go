@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterWaitBlocksPastBurst(t *testing.T) {
+	rl := NewRateLimiter(map[string]float64{"chat.postMessage": 10}, 1, 1)
+	ctx := context.Background()
+
+	if err := rl.Wait(ctx, "chat.postMessage", "C1"); err != nil {
+		t.Fatalf("first Wait() error = %v, want nil", err)
+	}
+
+	start := time.Now()
+	if err := rl.Wait(ctx, "chat.postMessage", "C1"); err != nil {
+		t.Fatalf("second Wait() error = %v, want nil", err)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("second Wait() returned after %v, want it to block for refill", elapsed)
+	}
+}
+
+func TestRateLimiterWaitIndependentPerChannel(t *testing.T) {
+	rl := NewRateLimiter(map[string]float64{"chat.postMessage": 1}, 1, 1)
+	ctx := context.Background()
+
+	if err := rl.Wait(ctx, "chat.postMessage", "C1"); err != nil {
+		t.Fatalf("Wait(C1) error = %v, want nil", err)
+	}
+
+	start := time.Now()
+	if err := rl.Wait(ctx, "chat.postMessage", "C2"); err != nil {
+		t.Fatalf("Wait(C2) error = %v, want nil", err)
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("Wait(C2) blocked for %v, want it unaffected by C1's bucket", elapsed)
+	}
+}
+
+func TestRateLimiterThrottlePausesBucket(t *testing.T) {
+	rl := NewRateLimiter(map[string]float64{"chat.postMessage": 1000}, 1000, 10)
+	rl.Throttle("chat.postMessage", "C1", 100*time.Millisecond)
+
+	start := time.Now()
+	if err := rl.Wait(context.Background(), "chat.postMessage", "C1"); err != nil {
+		t.Fatalf("Wait() error = %v, want nil", err)
+	}
+	if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+		t.Errorf("Wait() returned after %v, want it to respect the throttle pause", elapsed)
+	}
+}
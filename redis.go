@@ -3,7 +3,8 @@ package main
 import (
 	"context"
 	"encoding/json"
-	"log"
+	"fmt"
+	"log/slog"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -11,27 +12,29 @@ import (
 )
 
 // processMessages reads messages from Redis list and sends them to Slack
-func processMessages(ctx context.Context, rdb *redis.Client, slackClient *slack.Client, listKey string, timeBombChannel string) {
+func processMessages(ctx context.Context, rdb *redis.Client, slackClient *slack.Client, listKey string, timeBombChannel, deadLetterChannel string, rl *RateLimiter, ts *TemplateStore) {
 	for {
 		select {
 		case <-ctx.Done():
-			log.Println("Message processing stopped")
+			slog.Info("Message processing stopped")
 			return
 		default:
 			// BLPOP blocks until a message is available or timeout occurs
+			waitStart := time.Now()
 			result, err := rdb.BLPop(ctx, 5*time.Second, listKey).Result()
+			redisWaitSeconds.WithLabelValues(listKey).Observe(time.Since(waitStart).Seconds())
 			if err == redis.Nil {
 				// Timeout, no message available
 				continue
 			} else if err != nil {
-				log.Printf("Error reading from Redis: %v", err)
+				slog.Error(fmt.Sprintf("Error reading from Redis: %v", err))
 				time.Sleep(1 * time.Second)
 				continue
 			}
 
 			// result[0] is the key, result[1] is the value
 			if len(result) < 2 {
-				log.Println("Invalid result from Redis BLPOP")
+				slog.Error("Invalid result from Redis BLPOP")
 				continue
 			}
 
@@ -40,38 +43,40 @@ func processMessages(ctx context.Context, rdb *redis.Client, slackClient *slack.
 			// Parse the message
 			var msg SlackMessage
 			if err := json.Unmarshal([]byte(messageData), &msg); err != nil {
-				log.Printf("Error parsing message JSON: %v, data: %s", err, messageData)
+				slog.Error(fmt.Sprintf("Error parsing message JSON: %v, data: %s", err, messageData))
 				continue
 			}
 
 			// Send message to Slack
-			sendSlackMessage(ctx, slackClient, rdb, msg, timeBombChannel)
+			sendSlackMessage(ctx, slackClient, rdb, msg, timeBombChannel, deadLetterChannel, rl, ts)
 		}
 	}
 }
 
 // processReactions reads reaction messages from Redis list and adds reactions to Slack messages
-func processReactions(ctx context.Context, rdb *redis.Client, slackClient *slack.Client, listKey string) {
+func processReactions(ctx context.Context, rdb *redis.Client, slackClient *slack.Client, listKey, deadLetterChannel string, rl *RateLimiter) {
 	for {
 		select {
 		case <-ctx.Done():
-			log.Println("Reaction processing stopped")
+			slog.Info("Reaction processing stopped")
 			return
 		default:
 			// BLPOP blocks until a message is available or timeout occurs
+			waitStart := time.Now()
 			result, err := rdb.BLPop(ctx, 5*time.Second, listKey).Result()
+			redisWaitSeconds.WithLabelValues(listKey).Observe(time.Since(waitStart).Seconds())
 			if err == redis.Nil {
 				// Timeout, no message available
 				continue
 			} else if err != nil {
-				log.Printf("Error reading from Redis: %v", err)
+				slog.Error(fmt.Sprintf("Error reading from Redis: %v", err))
 				time.Sleep(1 * time.Second)
 				continue
 			}
 
 			// result[0] is the key, result[1] is the value
 			if len(result) < 2 {
-				log.Println("Invalid result from Redis BLPOP")
+				slog.Error("Invalid result from Redis BLPOP")
 				continue
 			}
 
@@ -80,12 +85,128 @@ func processReactions(ctx context.Context, rdb *redis.Client, slackClient *slack
 			// Parse the reaction message
 			var msg ReactionMessage
 			if err := json.Unmarshal([]byte(messageData), &msg); err != nil {
-				log.Printf("Error parsing reaction message JSON: %v, data: %s", err, messageData)
+				slog.Error(fmt.Sprintf("Error parsing reaction message JSON: %v, data: %s", err, messageData))
 				continue
 			}
 
 			// Add reaction to Slack
-			addSlackReaction(slackClient, msg)
+			addSlackReaction(ctx, slackClient, rdb, msg, deadLetterChannel, rl)
+		}
+	}
+}
+
+// processFiles reads file upload jobs from a Redis list and uploads them to Slack
+func processFiles(ctx context.Context, rdb *redis.Client, slackClient *slack.Client, listKey string, rl *RateLimiter) {
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("File processing stopped")
+			return
+		default:
+			// BLPOP blocks until a job is available or timeout occurs
+			waitStart := time.Now()
+			result, err := rdb.BLPop(ctx, 5*time.Second, listKey).Result()
+			redisWaitSeconds.WithLabelValues(listKey).Observe(time.Since(waitStart).Seconds())
+			if err == redis.Nil {
+				// Timeout, no job available
+				continue
+			} else if err != nil {
+				slog.Error(fmt.Sprintf("Error reading from Redis: %v", err))
+				time.Sleep(1 * time.Second)
+				continue
+			}
+
+			// result[0] is the key, result[1] is the value
+			if len(result) < 2 {
+				slog.Error("Invalid result from Redis BLPOP")
+				continue
+			}
+
+			jobData := result[1]
+
+			// Parse the upload job
+			var upload SlackFileUpload
+			if err := json.Unmarshal([]byte(jobData), &upload); err != nil {
+				slog.Error(fmt.Sprintf("Error parsing file upload JSON: %v, data: %s", err, jobData))
+				continue
+			}
+
+			// Upload the file to Slack
+			uploadSlackFile(ctx, slackClient, upload, rl)
+		}
+	}
+}
+
+// processUpdates reads message update jobs from a Redis list and edits the corresponding Slack messages
+func processUpdates(ctx context.Context, rdb *redis.Client, slackClient *slack.Client, listKey string, rl *RateLimiter) {
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("Update processing stopped")
+			return
+		default:
+			waitStart := time.Now()
+			result, err := rdb.BLPop(ctx, 5*time.Second, listKey).Result()
+			redisWaitSeconds.WithLabelValues(listKey).Observe(time.Since(waitStart).Seconds())
+			if err == redis.Nil {
+				continue
+			} else if err != nil {
+				slog.Error(fmt.Sprintf("Error reading from Redis: %v", err))
+				time.Sleep(1 * time.Second)
+				continue
+			}
+
+			if len(result) < 2 {
+				slog.Error("Invalid result from Redis BLPOP")
+				continue
+			}
+
+			messageData := result[1]
+
+			var msg UpdateMessage
+			if err := json.Unmarshal([]byte(messageData), &msg); err != nil {
+				slog.Error(fmt.Sprintf("Error parsing update message JSON: %v, data: %s", err, messageData))
+				continue
+			}
+
+			updateSlackMessage(ctx, slackClient, msg, rl)
+		}
+	}
+}
+
+// processDeletes reads message delete jobs from a Redis list and deletes the corresponding Slack messages
+func processDeletes(ctx context.Context, rdb *redis.Client, slackClient *slack.Client, listKey string, rl *RateLimiter) {
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("Delete processing stopped")
+			return
+		default:
+			waitStart := time.Now()
+			result, err := rdb.BLPop(ctx, 5*time.Second, listKey).Result()
+			redisWaitSeconds.WithLabelValues(listKey).Observe(time.Since(waitStart).Seconds())
+			if err == redis.Nil {
+				continue
+			} else if err != nil {
+				slog.Error(fmt.Sprintf("Error reading from Redis: %v", err))
+				time.Sleep(1 * time.Second)
+				continue
+			}
+
+			if len(result) < 2 {
+				slog.Error("Invalid result from Redis BLPOP")
+				continue
+			}
+
+			messageData := result[1]
+
+			var msg DeleteMessage
+			if err := json.Unmarshal([]byte(messageData), &msg); err != nil {
+				slog.Error(fmt.Sprintf("Error parsing delete message JSON: %v, data: %s", err, messageData))
+				continue
+			}
+
+			deleteSlackMessage(ctx, slackClient, msg, rl)
 		}
 	}
 }
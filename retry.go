@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/slack-go/slack"
+)
+
+const (
+	maxSendAttempts = 5
+	sendRetryBase   = 250 * time.Millisecond
+	sendRetryCap    = 4 * time.Second
+)
+
+// FailureRecord is published to the dead-letter channel when a Slack API call
+// exhausts all retry attempts, so an operator (or a replay tool) can inspect
+// or requeue the original payload without reconstructing it from logs alone.
+type FailureRecord struct {
+	Method  string          `json:"method"`
+	Payload json.RawMessage `json:"payload"`
+	Error   string          `json:"error"`
+}
+
+// callWithRetry calls attempt up to maxSendAttempts times. A
+// slack.RateLimitedError backs off for the server-suggested RetryAfter; any
+// other transient error backs off exponentially from sendRetryBase up to
+// sendRetryCap with jitter. A permanent Slack API error (see
+// isPermanentSlackError) is returned immediately without retrying, since
+// processMessages/processReactions process their Redis list on a single
+// goroutine and a doomed retry loop would otherwise stall every message
+// behind it. It returns the final error once attempts are exhausted or a
+// permanent error is seen, or nil on the first success.
+func callWithRetry(ctx context.Context, attempt func() error) error {
+	var err error
+	for i := 0; i < maxSendAttempts; i++ {
+		if i > 0 {
+			select {
+			case <-time.After(sendRetryDelay(i, err)):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		err = attempt()
+		if err == nil {
+			return nil
+		}
+		if isPermanentSlackError(err) {
+			return err
+		}
+	}
+	return err
+}
+
+// permanentSlackErrors lists Slack Web API error codes (the "error" field of
+// a non-ok response) that will never succeed on retry, as opposed to
+// transient ones like "fatal_error" or rate limiting.
+var permanentSlackErrors = map[string]bool{
+	"channel_not_found": true,
+	"not_in_channel":    true,
+	"is_archived":       true,
+	"invalid_auth":      true,
+	"account_inactive":  true,
+	"token_revoked":     true,
+	"missing_scope":     true,
+	"not_authed":        true,
+	"msg_too_long":      true,
+	"no_text":           true,
+	"restricted_action": true,
+}
+
+// isPermanentSlackError reports whether err is a Slack API failure that
+// retrying won't fix: a known-permanent error code, or a non-429 HTTP status
+// from the Slack web client. Anything else, including an unrecognized error
+// code, is treated as transient so it still gets retried.
+func isPermanentSlackError(err error) bool {
+	var statusErr slack.StatusCodeError
+	if errors.As(err, &statusErr) {
+		return !statusErr.Retryable()
+	}
+
+	var apiErr slack.SlackErrorResponse
+	if errors.As(err, &apiErr) {
+		return permanentSlackErrors[apiErr.Err]
+	}
+
+	return false
+}
+
+// sendRetryDelay returns how long to wait before the (1-indexed) attempt
+// number, given the error the previous attempt returned.
+func sendRetryDelay(attempt int, err error) time.Duration {
+	var rateLimitedErr *slack.RateLimitedError
+	if errors.As(err, &rateLimitedErr) && rateLimitedErr.RetryAfter > 0 {
+		return rateLimitedErr.RetryAfter
+	}
+
+	delay := sendRetryBase * time.Duration(math.Pow(2, float64(attempt-1)))
+	if delay > sendRetryCap {
+		delay = sendRetryCap
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// publishFailureRecord marshals payload alongside failErr and publishes it to
+// deadLetterChannel so a permanently failed send isn't dropped silently. It is
+// a no-op if rdb or deadLetterChannel aren't configured.
+func publishFailureRecord(ctx context.Context, rdb *redis.Client, deadLetterChannel, method string, payload interface{}, failErr error) {
+	if rdb == nil || deadLetterChannel == "" {
+		return
+	}
+
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		slog.Error(fmt.Sprintf("Error marshaling payload for dead-letter record: %v", err))
+		return
+	}
+
+	record := FailureRecord{Method: method, Payload: raw, Error: failErr.Error()}
+	recordPayload, err := json.Marshal(record)
+	if err != nil {
+		slog.Error(fmt.Sprintf("Error marshaling dead-letter record: %v", err))
+		return
+	}
+
+	if err := rdb.Publish(ctx, deadLetterChannel, string(recordPayload)).Err(); err != nil {
+		slog.Error(fmt.Sprintf("Error publishing dead-letter record to '%s': %v", deadLetterChannel, err))
+		return
+	}
+	slog.Warn(fmt.Sprintf("Dead-lettered %s failure to '%s': %v", method, deadLetterChannel, failErr))
+}
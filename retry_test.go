@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+func TestCallWithRetrySucceedsWithoutRetrying(t *testing.T) {
+	calls := 0
+	err := callWithRetry(context.Background(), func() error {
+		calls++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("callWithRetry() error = %v, want nil", err)
+	}
+	if calls != 1 {
+		t.Errorf("attempt() called %d times, want 1", calls)
+	}
+}
+
+func TestCallWithRetryGivesUpAfterMaxAttempts(t *testing.T) {
+	calls := 0
+	wantErr := errors.New("transient")
+	err := callWithRetry(context.Background(), func() error {
+		calls++
+		return wantErr
+	})
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("callWithRetry() error = %v, want %v", err, wantErr)
+	}
+	if calls != maxSendAttempts {
+		t.Errorf("attempt() called %d times, want %d", calls, maxSendAttempts)
+	}
+}
+
+func TestCallWithRetryStopsOnContextCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	calls := 0
+	err := callWithRetry(ctx, func() error {
+		calls++
+		return errors.New("transient")
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("callWithRetry() error = %v, want context.Canceled", err)
+	}
+	if calls != 1 {
+		t.Errorf("attempt() called %d times, want 1 (cancellation hit before the first retry sleep)", calls)
+	}
+}
+
+func TestSendRetryDelayHonorsRateLimitedRetryAfter(t *testing.T) {
+	err := &slack.RateLimitedError{RetryAfter: 2 * time.Second}
+	if got := sendRetryDelay(1, err); got != 2*time.Second {
+		t.Errorf("sendRetryDelay() = %v, want the server-suggested 2s", got)
+	}
+}
+
+func TestSendRetryDelayBacksOffExponentiallyWithCap(t *testing.T) {
+	err := errors.New("transient")
+	if got := sendRetryDelay(10, err); got > sendRetryCap {
+		t.Errorf("sendRetryDelay() = %v, want capped at %v", got, sendRetryCap)
+	}
+}
+
+func TestPublishFailureRecordNoopWithoutRedis(t *testing.T) {
+	// Should not panic when rdb/deadLetterChannel aren't configured.
+	publishFailureRecord(context.Background(), nil, "", "chat.postMessage", SlackMessage{Channel: "C1"}, errors.New("boom"))
+}
+
+func TestCallWithRetryStopsImmediatelyOnPermanentError(t *testing.T) {
+	calls := 0
+	err := callWithRetry(context.Background(), func() error {
+		calls++
+		return slack.SlackErrorResponse{Err: "channel_not_found"}
+	})
+	if err == nil || err.Error() != "channel_not_found" {
+		t.Fatalf("callWithRetry() error = %v, want channel_not_found", err)
+	}
+	if calls != 1 {
+		t.Errorf("attempt() called %d times, want 1 (permanent error shouldn't be retried)", calls)
+	}
+}
+
+func TestIsPermanentSlackError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"known permanent Slack error code", slack.SlackErrorResponse{Err: "invalid_auth"}, true},
+		{"unrecognized Slack error code", slack.SlackErrorResponse{Err: "fatal_error"}, false},
+		{"non-retryable HTTP status", slack.StatusCodeError{Code: 400, Status: "Bad Request"}, true},
+		{"retryable HTTP status", slack.StatusCodeError{Code: 503, Status: "Service Unavailable"}, false},
+		{"rate limited error", &slack.RateLimitedError{RetryAfter: time.Second}, false},
+		{"plain error", errors.New("boom"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isPermanentSlackError(tt.err); got != tt.want {
+				t.Errorf("isPermanentSlackError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -3,51 +3,143 @@ package main
 import (
 	"context"
 	"encoding/json"
-	"log"
+	"fmt"
+	"log/slog"
+	"time"
 
 	"github.com/redis/go-redis/v9"
 	"github.com/slack-go/slack"
 )
 
-// sendSlackMessage sends a message to Slack and optionally publishes to TimeBomb for deletion
-func sendSlackMessage(ctx context.Context, slackClient *slack.Client, rdb *redis.Client, msg SlackMessage, timeBombChannel string) {
+// sendSlackMessage sends a message to Slack and optionally publishes to TimeBomb for
+// deletion. It is the fire-and-forget entry point used by the Redis-backed
+// processMessages loop, where there is no caller waiting on the result.
+func sendSlackMessage(ctx context.Context, slackClient *slack.Client, rdb *redis.Client, msg SlackMessage, timeBombChannel, deadLetterChannel string, rl *RateLimiter, ts *TemplateStore) {
+	if _, _, err := sendSlackMessageWithResponse(ctx, slackClient, rdb, msg, timeBombChannel, deadLetterChannel, rl, ts); err != nil {
+		slog.Error(fmt.Sprintf("Error sending message: %v", err))
+	}
+}
+
+// sendSlackMessageWithResponse sends a message to Slack, optionally publishes to
+// TimeBomb for deletion, and returns the posted (channel, ts) pair so HTTP callers
+// can relay it back to the client. rl throttles the call to respect Slack's
+// per-channel rate limits; the post itself is retried with backoff on a 429 or
+// other transient error, and if every attempt fails the original message is
+// published to deadLetterChannel (if configured) rather than dropped silently.
+// ts renders msg.Template, if set, into msg.Text or msg.BlocksTemplate before
+// the message is otherwise validated and built.
+func sendSlackMessageWithResponse(ctx context.Context, slackClient *slack.Client, rdb *redis.Client, msg SlackMessage, timeBombChannel, deadLetterChannel string, rl *RateLimiter, ts *TemplateStore) (string, string, error) {
+	if msg.Template != "" {
+		if ts == nil {
+			return "", "", fmt.Errorf("%w: no template store configured", ErrInvalidMessage)
+		}
+		rendered, err := ts.Render(msg.Template, msg.TemplateData)
+		if err != nil {
+			return "", "", err
+		}
+		if msg.TemplateTarget == "blocks" {
+			if err := json.Unmarshal([]byte(rendered), &msg.BlocksTemplate); err != nil {
+				return "", "", fmt.Errorf("%w: template %q did not render valid blocks_template JSON: %v", ErrInvalidMessage, msg.Template, err)
+			}
+		} else {
+			msg.Text = rendered
+		}
+	}
+
 	// Validate message
-	if msg.Channel == "" || msg.Text == "" {
-		log.Printf("Invalid message: channel and text are required. Got: %+v", msg)
-		return
+	if msg.Channel == "" || (msg.Text == "" && len(msg.Blocks) == 0 && len(msg.BlocksTemplate) == 0 && len(msg.Attachments) == 0) {
+		return "", "", ErrInvalidMessage
 	}
 
 	// Validate TTL if provided
 	if msg.TTL < 0 {
-		log.Printf("Invalid message: ttl must be non-negative if provided. Got: %+v", msg)
-		return
+		return "", "", ErrInvalidTTL
 	}
 
-	// Send to Slack
-	log.Printf("Sending message to channel '%s': %s", msg.Channel, msg.Text)
-
 	// Build message options
 	msgOptions := []slack.MsgOption{
-		slack.MsgOptionText(msg.Text, false),
 		slack.MsgOptionDisableLinkUnfurl(),
 	}
 
+	if msg.Text != "" {
+		msgOptions = append(msgOptions, slack.MsgOptionText(msg.Text, false))
+	}
+
+	if len(msg.BlocksTemplate) > 0 {
+		blocks, err := buildBlocks(msg.BlocksTemplate)
+		if err != nil {
+			return "", "", err
+		}
+		msgOptions = append(msgOptions, slack.MsgOptionBlocks(blocks.BlockSet...))
+	} else if len(msg.Blocks) > 0 {
+		var blocks slack.Blocks
+		if err := json.Unmarshal(msg.Blocks, &blocks); err != nil {
+			return "", "", fmt.Errorf("%w: blocks is not valid Block Kit JSON: %v", ErrInvalidMessage, err)
+		}
+		msgOptions = append(msgOptions, slack.MsgOptionBlocks(blocks.BlockSet...))
+	}
+
+	if len(msg.Attachments) > 0 {
+		msgOptions = append(msgOptions, slack.MsgOptionAttachments(msg.Attachments...))
+	}
+
+	if msg.Username != "" {
+		msgOptions = append(msgOptions, slack.MsgOptionUsername(msg.Username))
+	}
+	if msg.IconEmoji != "" {
+		msgOptions = append(msgOptions, slack.MsgOptionIconEmoji(msg.IconEmoji))
+	}
+	if msg.IconURL != "" {
+		msgOptions = append(msgOptions, slack.MsgOptionIconURL(msg.IconURL))
+	}
+
+	if msg.ThreadTS != "" {
+		msgOptions = append(msgOptions, slack.MsgOptionTS(msg.ThreadTS))
+		if msg.ReplyBroadcast {
+			msgOptions = append(msgOptions, slack.MsgOptionBroadcast())
+		}
+	}
+
 	// Add metadata if provided
 	if msg.Metadata != nil {
-		log.Printf("Including metadata with event_type: %s", msg.Metadata.EventType)
+		slog.Info(fmt.Sprintf("Including metadata with event_type: %s", msg.Metadata.EventType))
 		msgOptions = append(msgOptions, slack.MsgOptionMetadata(slack.SlackMetadata{
 			EventType:    msg.Metadata.EventType,
 			EventPayload: msg.Metadata.EventPayload,
 		}))
 	}
 
-	channelID, timestamp, err := slackClient.PostMessage(msg.Channel, msgOptions...)
+	if msg.User != "" {
+		return sendSlackEphemeralMessage(ctx, slackClient, msg, rl, msgOptions)
+	}
+
+	slog.Info(fmt.Sprintf("Sending message to channel '%s'", msg.Channel))
+
+	var channelID, timestamp string
+	err := callWithRetry(ctx, func() error {
+		if rl != nil {
+			if err := rl.Wait(ctx, "chat.postMessage", msg.Channel); err != nil {
+				return fmt.Errorf("rate limit wait interrupted: %w", err)
+			}
+		}
+
+		apiStart := time.Now()
+		var apiErr error
+		channelID, timestamp, apiErr = slackClient.PostMessage(msg.Channel, msgOptions...)
+		slackAPILatencySeconds.WithLabelValues("chat.postMessage").Observe(time.Since(apiStart).Seconds())
+		if apiErr != nil && rl != nil {
+			rl.throttleIfRateLimited("chat.postMessage", msg.Channel, apiErr)
+		}
+		return apiErr
+	})
 	if err != nil {
-		log.Printf("Error posting to Slack: %v", err)
-		return
+		messagesPostedTotal.WithLabelValues(msg.Channel, "error").Inc()
+		publishFailureRecord(ctx, rdb, deadLetterChannel, "chat.postMessage", msg, err)
+		return "", "", fmt.Errorf("error posting to Slack: %w", err)
 	}
+	messagesPostedTotal.WithLabelValues(msg.Channel, "success").Inc()
 
-	log.Printf("Message sent successfully to channel %s (timestamp: %s)", channelID, timestamp)
+	slog.Info(fmt.Sprintf("Message sent successfully to channel %s (timestamp: %s)", channelID, timestamp))
 
 	// If TTL is specified, publish to TimeBomb for scheduled deletion
 	if msg.TTL > 0 {
@@ -59,39 +151,120 @@ func sendSlackMessage(ctx context.Context, slackClient *slack.Client, rdb *redis
 
 		tbPayload, err := json.Marshal(tbMsg)
 		if err != nil {
-			log.Printf("Error marshaling TimeBomb message: %v", err)
+			slog.Error(fmt.Sprintf("Error marshaling TimeBomb message: %v", err))
 		} else {
 			err = rdb.Publish(ctx, timeBombChannel, string(tbPayload)).Err()
 			if err != nil {
-				log.Printf("Error publishing to TimeBomb channel '%s': %v", timeBombChannel, err)
+				slog.Error(fmt.Sprintf("Error publishing to TimeBomb channel '%s': %v", timeBombChannel, err))
 			} else {
-				log.Printf("Published to TimeBomb for deletion: channel=%s, ts=%s, ttl=%ds", channelID, timestamp, msg.TTL)
+				timeBombDispatchedTotal.Inc()
+				slog.Info(fmt.Sprintf("Published to TimeBomb for deletion: channel=%s, ts=%s, ttl=%ds", channelID, timestamp, msg.TTL))
 			}
 		}
 	}
+
+	// If a reply channel is specified, publish the posted (channel, ts) pair so
+	// downstream publishers can reply into the same thread.
+	if msg.ReplyChannel != "" {
+		replyMsg := MessageResponse{
+			Channel: channelID,
+			TS:      timestamp,
+		}
+
+		replyPayload, err := json.Marshal(replyMsg)
+		if err != nil {
+			slog.Error(fmt.Sprintf("Error marshaling reply message: %v", err))
+		} else if err := rdb.Publish(ctx, msg.ReplyChannel, string(replyPayload)).Err(); err != nil {
+			slog.Error(fmt.Sprintf("Error publishing to reply channel '%s': %v", msg.ReplyChannel, err))
+		}
+	}
+
+	return channelID, timestamp, nil
+}
+
+// sendSlackEphemeralMessage posts msg to msg.User via PostEphemeral, visible
+// only to that user. Ephemeral messages have no deletable timestamp from the
+// recipient's perspective, so TTL/TimeBomb and ReplyChannel are not supported
+// here; a TTL on an ephemeral message is logged and ignored rather than
+// silently dropped.
+func sendSlackEphemeralMessage(ctx context.Context, slackClient *slack.Client, msg SlackMessage, rl *RateLimiter, msgOptions []slack.MsgOption) (string, string, error) {
+	if msg.TTL > 0 {
+		slog.Warn(fmt.Sprintf("Ignoring ttl=%ds for ephemeral message to user '%s' in channel '%s': ephemeral messages cannot be deleted by TimeBomb", msg.TTL, msg.User, msg.Channel))
+	}
+
+	if rl != nil {
+		if err := rl.Wait(ctx, "chat.postEphemeral", msg.Channel); err != nil {
+			return "", "", fmt.Errorf("rate limit wait interrupted: %w", err)
+		}
+	}
+
+	slog.Info(fmt.Sprintf("Sending ephemeral message to user '%s' in channel '%s'", msg.User, msg.Channel))
+
+	apiStart := time.Now()
+	timestamp, err := slackClient.PostEphemeral(msg.Channel, msg.User, msgOptions...)
+	slackAPILatencySeconds.WithLabelValues("chat.postEphemeral").Observe(time.Since(apiStart).Seconds())
+	if err != nil {
+		if rl != nil {
+			rl.throttleIfRateLimited("chat.postEphemeral", msg.Channel, err)
+		}
+		messagesPostedTotal.WithLabelValues(msg.Channel, "error").Inc()
+		return "", "", fmt.Errorf("error posting ephemeral message to Slack: %w", err)
+	}
+	messagesPostedTotal.WithLabelValues(msg.Channel, "success").Inc()
+
+	slog.Info(fmt.Sprintf("Ephemeral message sent successfully to user %s in channel %s (timestamp: %s)", msg.User, msg.Channel, timestamp))
+	return msg.Channel, timestamp, nil
+}
+
+// addSlackReaction adds an emoji reaction to a Slack message. It is the
+// fire-and-forget entry point used by the Redis-backed processReactions loop.
+func addSlackReaction(ctx context.Context, slackClient *slack.Client, rdb *redis.Client, msg ReactionMessage, deadLetterChannel string, rl *RateLimiter) {
+	if err := addSlackReactionWithResponse(ctx, slackClient, rdb, msg, deadLetterChannel, rl); err != nil {
+		slog.Error(fmt.Sprintf("Error adding reaction: %v", err))
+	}
 }
 
-// addSlackReaction adds an emoji reaction to a Slack message
-func addSlackReaction(slackClient *slack.Client, msg ReactionMessage) {
+// addSlackReactionWithResponse adds an emoji reaction to a Slack message and
+// reports whether it succeeded, so callers that need to act on failure (retry
+// loops, HTTP handlers) can do so. rl throttles the call to respect Slack's
+// per-channel rate limits; the call itself is retried with backoff on a 429
+// or other transient error, and if every attempt fails the original message is
+// published to deadLetterChannel (if configured) rather than dropped silently.
+func addSlackReactionWithResponse(ctx context.Context, slackClient *slack.Client, rdb *redis.Client, msg ReactionMessage, deadLetterChannel string, rl *RateLimiter) error {
 	// Validate message
 	if msg.Reaction == "" || msg.Channel == "" || msg.TS == "" {
-		log.Printf("Invalid reaction message: reaction, channel, and ts are required. Got: %+v", msg)
-		return
+		return fmt.Errorf("%w: reaction, channel, and ts are required", ErrInvalidMessage)
 	}
 
-	// Add reaction to Slack
-	log.Printf("Adding reaction '%s' to message in channel '%s' at timestamp '%s'", msg.Reaction, msg.Channel, msg.TS)
+	slog.Info(fmt.Sprintf("Adding reaction '%s' to message in channel '%s' at timestamp '%s'", msg.Reaction, msg.Channel, msg.TS))
 
 	itemRef := slack.ItemRef{
 		Channel:   msg.Channel,
 		Timestamp: msg.TS,
 	}
 
-	err := slackClient.AddReaction(msg.Reaction, itemRef)
+	err := callWithRetry(ctx, func() error {
+		if rl != nil {
+			if err := rl.Wait(ctx, "reactions.add", msg.Channel); err != nil {
+				return fmt.Errorf("rate limit wait interrupted: %w", err)
+			}
+		}
+
+		apiStart := time.Now()
+		apiErr := slackClient.AddReaction(msg.Reaction, itemRef)
+		slackAPILatencySeconds.WithLabelValues("reactions.add").Observe(time.Since(apiStart).Seconds())
+		if apiErr != nil && rl != nil {
+			rl.throttleIfRateLimited("reactions.add", msg.Channel, apiErr)
+		}
+		return apiErr
+	})
 	if err != nil {
-		log.Printf("Error adding reaction to Slack: %v", err)
-		return
+		reactionsAddedTotal.WithLabelValues(msg.Channel, "error").Inc()
+		publishFailureRecord(ctx, rdb, deadLetterChannel, "reactions.add", msg, err)
+		return fmt.Errorf("error adding reaction to Slack: %w", err)
 	}
+	reactionsAddedTotal.WithLabelValues(msg.Channel, "success").Inc()
 
-	log.Printf("Reaction '%s' added successfully to channel %s (timestamp: %s)", msg.Reaction, msg.Channel, msg.TS)
+	slog.Info(fmt.Sprintf("Reaction '%s' added successfully to channel %s (timestamp: %s)", msg.Reaction, msg.Channel, msg.TS))
+	return nil
 }
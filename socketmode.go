@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+	"github.com/slack-go/slack/socketmode"
+)
+
+// SocketModeConfig controls which inbound events processSocketMode forwards to
+// Redis. An empty allowlist means allow everything.
+type SocketModeConfig struct {
+	EventTypeAllowlist map[string]bool
+	ChannelAllowlist   map[string]bool
+	ListKeyPrefix      string // events are pushed to "<prefix><event type>"
+}
+
+// allows reports whether an event of the given type, originating in the given
+// channel, should be forwarded according to cfg's allowlists.
+func (cfg SocketModeConfig) allows(eventType, channel string) bool {
+	if len(cfg.EventTypeAllowlist) > 0 && !cfg.EventTypeAllowlist[eventType] {
+		return false
+	}
+	if len(cfg.ChannelAllowlist) > 0 && channel != "" && !cfg.ChannelAllowlist[channel] {
+		return false
+	}
+	return true
+}
+
+// eventChannel extracts the channel ID from the inner event types processSocketMode
+// understands, returning "" when an event has no associated channel.
+func eventChannel(inner interface{}) string {
+	switch ev := inner.(type) {
+	case *slackevents.MessageEvent:
+		return ev.Channel
+	case *slackevents.ReactionAddedEvent:
+		return ev.Item.Channel
+	case *slackevents.AppMentionEvent:
+		return ev.Channel
+	case *slackevents.MemberJoinedChannelEvent:
+		return ev.Channel
+	default:
+		return ""
+	}
+}
+
+// processSocketMode opens a Slack Socket Mode connection using an app-level token
+// and streams incoming Events API events (message, reaction_added, app_mention,
+// member_joined_channel) into Redis lists keyed by event type, e.g.
+// "slack_events:message". It runs until ctx is cancelled.
+func processSocketMode(ctx context.Context, rdb *redis.Client, appToken, botToken string, cfg SocketModeConfig) {
+	client := slack.New(botToken, slack.OptionAppLevelToken(appToken))
+	socketClient := socketmode.New(client)
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case evt := <-socketClient.Events:
+				switch evt.Type {
+				case socketmode.EventTypeEventsAPI:
+					eventsAPIEvent, ok := evt.Data.(slackevents.EventsAPIEvent)
+					if !ok {
+						continue
+					}
+					socketClient.Ack(*evt.Request)
+					handleSocketModeEvent(ctx, rdb, cfg, eventsAPIEvent)
+				case socketmode.EventTypeConnecting:
+					slog.Info("Socket Mode: connecting to Slack...")
+				case socketmode.EventTypeConnectionError:
+					slog.Error("Socket Mode: connection error, retrying...")
+				case socketmode.EventTypeConnected:
+					slog.Info("Socket Mode: connected")
+				}
+			}
+		}
+	}()
+
+	slog.Info("Starting Socket Mode event loop...")
+	if err := socketClient.RunContext(ctx); err != nil && ctx.Err() == nil {
+		slog.Info(fmt.Sprintf("Socket Mode connection ended: %v", err))
+	}
+}
+
+// handleSocketModeEvent dispatches a single Events API inner event to Redis if
+// it passes cfg's event type and channel allowlists.
+func handleSocketModeEvent(ctx context.Context, rdb *redis.Client, cfg SocketModeConfig, eventsAPIEvent slackevents.EventsAPIEvent) {
+	innerEvent := eventsAPIEvent.InnerEvent
+	eventType := innerEvent.Type
+	channel := eventChannel(innerEvent.Data)
+
+	if !cfg.allows(eventType, channel) {
+		return
+	}
+
+	payload, err := json.Marshal(innerEvent.Data)
+	if err != nil {
+		slog.Error(fmt.Sprintf("Error marshaling Socket Mode event of type '%s': %v", eventType, err))
+		return
+	}
+
+	listKey := cfg.ListKeyPrefix + eventType
+	if err := rdb.RPush(ctx, listKey, string(payload)).Err(); err != nil {
+		slog.Error(fmt.Sprintf("Error pushing Socket Mode event to Redis list '%s': %v", listKey, err))
+		return
+	}
+
+	slog.Info(fmt.Sprintf("Queued Socket Mode event '%s' to Redis list '%s'", eventType, listKey))
+}
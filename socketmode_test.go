@@ -0,0 +1,53 @@
+package main
+
+import "testing"
+
+func TestParseAllowlist(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  map[string]bool
+	}{
+		{name: "empty", value: "", want: map[string]bool{}},
+		{name: "single", value: "message", want: map[string]bool{"message": true}},
+		{name: "multiple with spaces", value: "message, reaction_added ,app_mention", want: map[string]bool{
+			"message": true, "reaction_added": true, "app_mention": true,
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseAllowlist(tt.value)
+			if len(got) != len(tt.want) {
+				t.Fatalf("parseAllowlist(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+			for k := range tt.want {
+				if !got[k] {
+					t.Errorf("parseAllowlist(%q) missing key %q", tt.value, k)
+				}
+			}
+		})
+	}
+}
+
+func TestSocketModeConfigAllows(t *testing.T) {
+	cfg := SocketModeConfig{
+		EventTypeAllowlist: map[string]bool{"message": true},
+		ChannelAllowlist:   map[string]bool{"C123": true},
+	}
+
+	if !cfg.allows("message", "C123") {
+		t.Error("allows(message, C123) = false, want true")
+	}
+	if cfg.allows("reaction_added", "C123") {
+		t.Error("allows(reaction_added, C123) = true, want false")
+	}
+	if cfg.allows("message", "C999") {
+		t.Error("allows(message, C999) = true, want false")
+	}
+
+	open := SocketModeConfig{}
+	if !open.allows("anything", "anywhere") {
+		t.Error("allows() with empty allowlists = false, want true")
+	}
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// StreamConfig bundles the Redis Streams tunables for the opt-in reliable
+// delivery path enabled by REDIS_MODE=stream.
+type StreamConfig struct {
+	Group         string
+	Consumer      string
+	MaxRetries    int
+	DeadLetterKey string
+	ClaimMinIdle  time.Duration
+	ClaimInterval time.Duration
+}
+
+// streamEnvelope is the JSON stored in each stream entry's "payload" field. It
+// wraps the caller's message with a retry counter that survives requeues,
+// since a stream entry's fields can't be mutated in place.
+type streamEnvelope struct {
+	Payload json.RawMessage `json:"payload"`
+	Retries int             `json:"retries"`
+}
+
+// ensureConsumerGroup creates streamKey's consumer group if it doesn't already exist.
+func ensureConsumerGroup(ctx context.Context, rdb *redis.Client, streamKey, group string) error {
+	err := rdb.XGroupCreateMkStream(ctx, streamKey, group, "$").Err()
+	if err != nil && !strings.Contains(err.Error(), "BUSYGROUP") {
+		return err
+	}
+	return nil
+}
+
+// processStream runs the generic Redis Streams reliable-delivery loop for
+// streamKey: XREADGROUP for new entries, a periodic XAUTOCLAIM pass to reclaim
+// entries abandoned by crashed consumers, exponential-backoff retries tracked
+// in the envelope, and an XADD to cfg.DeadLetterKey once cfg.MaxRetries is
+// exceeded. handle is called with the raw payload bytes and should return a
+// non-nil error if delivery failed. It runs until ctx is cancelled.
+func processStream(ctx context.Context, rdb *redis.Client, streamKey string, cfg StreamConfig, handle func([]byte) error) {
+	if err := ensureConsumerGroup(ctx, rdb, streamKey, cfg.Group); err != nil {
+		slog.Error(fmt.Sprintf("Error creating consumer group '%s' on stream '%s': %v", cfg.Group, streamKey, err))
+		return
+	}
+
+	claimTicker := time.NewTicker(cfg.ClaimInterval)
+	defer claimTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info(fmt.Sprintf("Stream processing for '%s' stopped", streamKey))
+			return
+		case <-claimTicker.C:
+			reclaimPending(ctx, rdb, streamKey, cfg, handle)
+		default:
+			streams, err := rdb.XReadGroup(ctx, &redis.XReadGroupArgs{
+				Group:    cfg.Group,
+				Consumer: cfg.Consumer,
+				Streams:  []string{streamKey, ">"},
+				Count:    10,
+				Block:    5 * time.Second,
+			}).Result()
+			if err == redis.Nil {
+				continue
+			} else if err != nil {
+				slog.Error(fmt.Sprintf("Error reading from stream '%s': %v", streamKey, err))
+				time.Sleep(1 * time.Second)
+				continue
+			}
+
+			for _, stream := range streams {
+				for _, msg := range stream.Messages {
+					processStreamEntry(ctx, rdb, streamKey, msg, cfg, handle)
+				}
+			}
+		}
+	}
+}
+
+// reclaimPending claims pending entries that have been idle for longer than
+// cfg.ClaimMinIdle (i.e. abandoned by a crashed consumer) and reprocesses them.
+func reclaimPending(ctx context.Context, rdb *redis.Client, streamKey string, cfg StreamConfig, handle func([]byte) error) {
+	cursor := "0"
+	for {
+		messages, nextCursor, err := rdb.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+			Stream:   streamKey,
+			Group:    cfg.Group,
+			Consumer: cfg.Consumer,
+			MinIdle:  cfg.ClaimMinIdle,
+			Start:    cursor,
+			Count:    10,
+		}).Result()
+		if err != nil {
+			if err != redis.Nil {
+				slog.Error(fmt.Sprintf("Error reclaiming pending entries on '%s': %v", streamKey, err))
+			}
+			return
+		}
+
+		for _, msg := range messages {
+			processStreamEntry(ctx, rdb, streamKey, msg, cfg, handle)
+		}
+
+		if nextCursor == "0" || len(messages) == 0 {
+			return
+		}
+		cursor = nextCursor
+	}
+}
+
+// processStreamEntry decodes a single stream entry's envelope, invokes handle,
+// and on failure either requeues it with an incremented retry counter after an
+// exponential backoff, or dead-letters it once cfg.MaxRetries is exceeded.
+// Either way the original entry is XACK'd so it leaves the pending list.
+func processStreamEntry(ctx context.Context, rdb *redis.Client, streamKey string, msg redis.XMessage, cfg StreamConfig, handle func([]byte) error) {
+	raw, _ := msg.Values["payload"].(string)
+
+	var env streamEnvelope
+	if err := json.Unmarshal([]byte(raw), &env); err != nil {
+		slog.Error(fmt.Sprintf("Error parsing stream envelope on '%s': %v, data: %s", streamKey, err, raw))
+		rdb.XAck(ctx, streamKey, cfg.Group, msg.ID)
+		return
+	}
+
+	if err := handle(env.Payload); err == nil {
+		rdb.XAck(ctx, streamKey, cfg.Group, msg.ID)
+		return
+	} else {
+		slog.Error(fmt.Sprintf("Error handling stream entry %s on '%s' (retry %d): %v", msg.ID, streamKey, env.Retries, err))
+
+		if env.Retries >= cfg.MaxRetries {
+			deadLetter := map[string]interface{}{
+				"payload": string(env.Payload),
+				"retries": env.Retries,
+				"error":   err.Error(),
+			}
+			if _, dlErr := rdb.XAdd(ctx, &redis.XAddArgs{Stream: cfg.DeadLetterKey, Values: deadLetter}).Result(); dlErr != nil {
+				slog.Error(fmt.Sprintf("Error dead-lettering entry %s to '%s': %v", msg.ID, cfg.DeadLetterKey, dlErr))
+			} else {
+				slog.Info(fmt.Sprintf("Dead-lettered entry %s to '%s' after %d retries", msg.ID, cfg.DeadLetterKey, env.Retries))
+			}
+			rdb.XAck(ctx, streamKey, cfg.Group, msg.ID)
+			return
+		}
+
+		time.Sleep(retryBackoff(env.Retries))
+
+		env.Retries++
+		retryPayload, marshalErr := json.Marshal(env)
+		if marshalErr != nil {
+			slog.Error(fmt.Sprintf("Error marshaling retry envelope for entry %s on '%s': %v", msg.ID, streamKey, marshalErr))
+		} else if _, addErr := rdb.XAdd(ctx, &redis.XAddArgs{Stream: streamKey, Values: map[string]interface{}{"payload": string(retryPayload)}}).Result(); addErr != nil {
+			slog.Error(fmt.Sprintf("Error requeueing entry %s on '%s': %v", msg.ID, streamKey, addErr))
+		}
+		rdb.XAck(ctx, streamKey, cfg.Group, msg.ID)
+	}
+}
+
+// retryBackoff returns an exponential backoff duration for the given retry
+// count, starting at 250ms and doubling each attempt.
+func retryBackoff(retries int) time.Duration {
+	return time.Duration(math.Pow(2, float64(retries))) * 250 * time.Millisecond
+}
@@ -0,0 +1,22 @@
+package main
+
+import "testing"
+
+func TestRetryBackoff(t *testing.T) {
+	tests := []struct {
+		retries int
+		want    string
+	}{
+		{retries: 0, want: "250ms"},
+		{retries: 1, want: "500ms"},
+		{retries: 2, want: "1s"},
+		{retries: 4, want: "4s"},
+	}
+
+	for _, tt := range tests {
+		got := retryBackoff(tt.retries)
+		if got.String() != tt.want {
+			t.Errorf("retryBackoff(%d) = %v, want %v", tt.retries, got, tt.want)
+		}
+	}
+}
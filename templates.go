@@ -0,0 +1,153 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"text/template"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// templateFuncs are the Slack-specific helpers available to every template,
+// on top of the text/template builtins.
+var templateFuncs = template.FuncMap{
+	"mention":     func(userID string) string { return fmt.Sprintf("<@%s>", userID) },
+	"channelLink": func(channelID string) string { return fmt.Sprintf("<#%s>", channelID) },
+	"escape":      escapeMrkdwn,
+	"fmtTS":       fmtTS,
+}
+
+// escapeMrkdwn escapes the three characters Slack's mrkdwn format requires to
+// be escaped in that order: &, <, then >. Apply it to any user-controlled
+// text interpolated into a template.
+func escapeMrkdwn(s string) string {
+	s = strings.ReplaceAll(s, "&", "&amp;")
+	s = strings.ReplaceAll(s, "<", "&lt;")
+	s = strings.ReplaceAll(s, ">", "&gt;")
+	return s
+}
+
+// fmtTS renders a Unix timestamp (seconds) using Slack's <!date> syntax, which
+// Slack clients localize to the viewer's own timezone.
+func fmtTS(unixSeconds int64) string {
+	return fmt.Sprintf("<!date^%d^{date_num} {time_secs}|%s>", unixSeconds, time.Unix(unixSeconds, 0).UTC().Format(time.RFC3339))
+}
+
+// TemplateStore holds named message templates loaded from a Redis hash and/or
+// a config directory, rendered with text/template plus templateFuncs. It is
+// safe for concurrent use and can be reloaded in place via Load*.
+type TemplateStore struct {
+	mu        sync.RWMutex
+	templates map[string]*template.Template
+}
+
+// NewTemplateStore returns an empty TemplateStore ready to be populated with
+// LoadDir and/or LoadRedis.
+func NewTemplateStore() *TemplateStore {
+	return &TemplateStore{templates: make(map[string]*template.Template)}
+}
+
+func (ts *TemplateStore) set(name, body string) error {
+	tmpl, err := template.New(name).Funcs(templateFuncs).Parse(body)
+	if err != nil {
+		return fmt.Errorf("parsing template %q: %w", name, err)
+	}
+	ts.mu.Lock()
+	ts.templates[name] = tmpl
+	ts.mu.Unlock()
+	return nil
+}
+
+// Render executes the named template against data and returns the rendered
+// text. It wraps ErrInvalidMessage when the template is unknown, so HTTP/Redis
+// callers can tell a bad request from a Slack-side failure.
+func (ts *TemplateStore) Render(name string, data map[string]interface{}) (string, error) {
+	ts.mu.RLock()
+	tmpl, ok := ts.templates[name]
+	ts.mu.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("%w: unknown template %q", ErrInvalidMessage, name)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("rendering template %q: %w", name, err)
+	}
+	return buf.String(), nil
+}
+
+// LoadDir (re)loads every regular file in dir as a template named after its
+// base filename without extension (e.g. "order_created.tmpl" -> "order_created").
+func (ts *TemplateStore) LoadDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("reading template dir %q: %w", dir, err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		body, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return fmt.Errorf("reading template file %q: %w", entry.Name(), err)
+		}
+		name := strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+		if err := ts.set(name, string(body)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LoadRedis (re)loads every field of the hashKey hash as a named template,
+// where the field name is the template name and the value is its
+// text/template body (e.g. `slackliner:templates`).
+func (ts *TemplateStore) LoadRedis(ctx context.Context, rdb *redis.Client, hashKey string) error {
+	fields, err := rdb.HGetAll(ctx, hashKey).Result()
+	if err != nil {
+		return fmt.Errorf("reading template hash %q: %w", hashKey, err)
+	}
+
+	for name, body := range fields {
+		if err := ts.set(name, body); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// watchSIGHUP reloads ts from dir and/or the Redis hash every time the process
+// receives SIGHUP, so operators can roll out new templates without a restart.
+func watchSIGHUP(ctx context.Context, ts *TemplateStore, rdb *redis.Client, dir, hashKey string) {
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGHUP)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sigChan:
+			slog.Info("Reloading message templates on SIGHUP")
+			if dir != "" {
+				if err := ts.LoadDir(dir); err != nil {
+					slog.Error(fmt.Sprintf("Error reloading templates from dir '%s': %v", dir, err))
+				}
+			}
+			if rdb != nil && hashKey != "" {
+				if err := ts.LoadRedis(ctx, rdb, hashKey); err != nil {
+					slog.Error(fmt.Sprintf("Error reloading templates from Redis hash '%s': %v", hashKey, err))
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTemplateStoreRender(t *testing.T) {
+	ts := NewTemplateStore()
+	if err := ts.set("greeting", "Hello {{mention .UserID}}, welcome to {{channelLink .ChannelID}}!"); err != nil {
+		t.Fatalf("set() error = %v", err)
+	}
+
+	got, err := ts.Render("greeting", map[string]interface{}{
+		"UserID":    "U123",
+		"ChannelID": "C456",
+	})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	want := "Hello <@U123>, welcome to <#C456>!"
+	if got != want {
+		t.Errorf("Render() = %q, want %q", got, want)
+	}
+}
+
+func TestTemplateStoreRenderUnknownTemplate(t *testing.T) {
+	ts := NewTemplateStore()
+
+	_, err := ts.Render("missing", nil)
+	if !errors.Is(err, ErrInvalidMessage) {
+		t.Errorf("Render() error = %v, want wrapped ErrInvalidMessage", err)
+	}
+}
+
+func TestEscapeMrkdwn(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "ampersand", input: "Tom & Jerry", want: "Tom &amp; Jerry"},
+		{name: "angle brackets", input: "<script>", want: "&lt;script&gt;"},
+		{name: "combined", input: "a < b & b > c", want: "a &lt; b &amp; b &gt; c"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := escapeMrkdwn(tt.input); got != tt.want {
+				t.Errorf("escapeMrkdwn(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
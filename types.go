@@ -3,12 +3,15 @@ package main
 import (
 	"encoding/json"
 	"errors"
+
+	"github.com/slack-go/slack"
 )
 
 // Error definitions
 var (
-	ErrInvalidMessage = errors.New("invalid message: channel and either text or blocks are required")
-	ErrInvalidTTL     = errors.New("invalid message: ttl must be non-negative")
+	ErrInvalidMessage      = errors.New("invalid message: channel and either text or blocks are required")
+	ErrInvalidTTL          = errors.New("invalid message: ttl must be non-negative")
+	ErrSigningSecretNotSet = errors.New("slack signing secret is not configured")
 )
 
 // MessageMetadata represents optional metadata to attach to a Slack message
@@ -26,12 +29,23 @@ type TimeBombMessage struct {
 
 // SlackMessage represents the payload structure expected from Redis for posting messages
 type SlackMessage struct {
-	Channel  string           `json:"channel"`
-	Text     string           `json:"text,omitempty"`
-	Blocks   json.RawMessage  `json:"blocks,omitempty"`   // Slack Block Kit blocks as JSON array
-	ThreadTS string           `json:"thread_ts,omitempty"` // Thread timestamp to reply to an existing thread
-	Metadata *MessageMetadata `json:"metadata,omitempty"`
-	TTL      int              `json:"ttl,omitempty"` // Time-to-live in seconds for automatic deletion via TimeBomb
+	Channel        string                 `json:"channel"`
+	Text           string                 `json:"text,omitempty"`
+	Blocks         json.RawMessage        `json:"blocks,omitempty"`          // Slack Block Kit blocks as JSON array
+	BlocksTemplate []BlockSpec            `json:"blocks_template,omitempty"` // simplified block descriptions compiled into Block Kit by buildBlocks
+	Attachments    []slack.Attachment     `json:"attachments,omitempty"`     // legacy secondary attachments (colored side-bars, fields)
+	ThreadTS       string                 `json:"thread_ts,omitempty"`       // Thread timestamp to reply to an existing thread
+	Metadata       *MessageMetadata       `json:"metadata,omitempty"`
+	TTL            int                    `json:"ttl,omitempty"`             // Time-to-live in seconds for automatic deletion via TimeBomb
+	Template       string                 `json:"template,omitempty"`        // name of a template registered in the TemplateStore
+	TemplateData   map[string]interface{} `json:"data,omitempty"`            // data passed to the template when rendering
+	TemplateTarget string                 `json:"template_target,omitempty"` // "text" (default) or "blocks": where the rendered template output is applied
+	Username       string                 `json:"username,omitempty"`        // overrides the bot's display name for this message only
+	IconEmoji      string                 `json:"icon_emoji,omitempty"`      // overrides the bot's icon with an emoji (e.g. ":ghost:") for this message only
+	IconURL        string                 `json:"icon_url,omitempty"`        // overrides the bot's icon with an image URL for this message only
+	ReplyBroadcast bool                   `json:"reply_broadcast,omitempty"` // also shows a threaded reply (ThreadTS) in the channel
+	ReplyChannel   string                 `json:"reply_channel,omitempty"`   // Redis pub/sub channel to publish the posted (channel, ts) pair to, so a follow-up can reply into the same thread
+	User           string                 `json:"user,omitempty"`            // if set, the message is posted ephemerally and is only visible to this user ID
 }
 
 // ReactionMessage represents the payload structure for adding emoji reactions
@@ -46,3 +60,43 @@ type MessageResponse struct {
 	Channel string `json:"channel"`
 	TS      string `json:"ts"`
 }
+
+// SlackFileUpload represents the payload structure expected from Redis (or the
+// POST /file HTTP endpoint) for uploading a file. Content and URL are
+// mutually exclusive: Content is base64-encoded inline file data, URL is
+// fetched by the handler at upload time.
+type SlackFileUpload struct {
+	Channels       []string `json:"channels"`
+	Filename       string   `json:"filename"`
+	Filetype       string   `json:"filetype,omitempty"` // Slack file type hint (e.g. "text", "go", "diff") used to apply snippet syntax highlighting
+	Title          string   `json:"title,omitempty"`
+	InitialComment string   `json:"initial_comment,omitempty"`
+	ThreadTS       string   `json:"thread_ts,omitempty"`
+	Content        string   `json:"content,omitempty"` // base64-encoded file content
+	URL            string   `json:"url,omitempty"`     // URL to fetch file content from
+}
+
+// FileResponse represents the HTTP response after uploading a file
+type FileResponse struct {
+	FileID    string `json:"file_id"`
+	Permalink string `json:"permalink,omitempty"`
+}
+
+// UpdateMessage represents the payload structure expected from Redis for
+// editing a previously posted message in place (e.g. "build running..." ->
+// "build passed ✅"), rather than deleting and reposting it.
+type UpdateMessage struct {
+	Channel        string             `json:"channel"`
+	TS             string             `json:"ts"`
+	Text           string             `json:"text,omitempty"`
+	Blocks         json.RawMessage    `json:"blocks,omitempty"`          // Slack Block Kit blocks as JSON array
+	BlocksTemplate []BlockSpec        `json:"blocks_template,omitempty"` // simplified block descriptions compiled into Block Kit by buildBlocks
+	Attachments    []slack.Attachment `json:"attachments,omitempty"`     // legacy secondary attachments (colored side-bars, fields)
+}
+
+// DeleteMessage represents the payload structure expected from Redis for
+// deleting a previously posted message.
+type DeleteMessage struct {
+	Channel string `json:"channel"`
+	TS      string `json:"ts"`
+}
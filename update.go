@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+// updateSlackMessage edits a previously posted message in place and logs the
+// outcome. It is the fire-and-forget entry point used by the Redis-backed
+// processUpdates loop, where there is no caller waiting on the result.
+func updateSlackMessage(ctx context.Context, slackClient *slack.Client, msg UpdateMessage, rl *RateLimiter) {
+	if _, _, err := updateSlackMessageWithResponse(ctx, slackClient, msg, rl); err != nil {
+		slog.Error(fmt.Sprintf("Error updating message: %v", err))
+	}
+}
+
+// updateSlackMessageWithResponse edits a previously posted message and returns
+// the updated (channel, ts) pair so HTTP callers can relay it back to the
+// client. rl throttles the call to respect Slack's per-channel rate limits and
+// backs off further on a 429 response.
+func updateSlackMessageWithResponse(ctx context.Context, slackClient *slack.Client, msg UpdateMessage, rl *RateLimiter) (string, string, error) {
+	if msg.Channel == "" || msg.TS == "" {
+		return "", "", fmt.Errorf("%w: channel and ts are required", ErrInvalidMessage)
+	}
+
+	msgOptions := []slack.MsgOption{
+		slack.MsgOptionDisableLinkUnfurl(),
+	}
+
+	if msg.Text != "" {
+		msgOptions = append(msgOptions, slack.MsgOptionText(msg.Text, false))
+	}
+
+	if len(msg.BlocksTemplate) > 0 {
+		blocks, err := buildBlocks(msg.BlocksTemplate)
+		if err != nil {
+			return "", "", err
+		}
+		msgOptions = append(msgOptions, slack.MsgOptionBlocks(blocks.BlockSet...))
+	} else if len(msg.Blocks) > 0 {
+		var blocks slack.Blocks
+		if err := json.Unmarshal(msg.Blocks, &blocks); err != nil {
+			return "", "", fmt.Errorf("%w: blocks is not valid Block Kit JSON: %v", ErrInvalidMessage, err)
+		}
+		msgOptions = append(msgOptions, slack.MsgOptionBlocks(blocks.BlockSet...))
+	}
+
+	if len(msg.Attachments) > 0 {
+		msgOptions = append(msgOptions, slack.MsgOptionAttachments(msg.Attachments...))
+	}
+
+	if rl != nil {
+		if err := rl.Wait(ctx, "chat.update", msg.Channel); err != nil {
+			return "", "", fmt.Errorf("rate limit wait interrupted: %w", err)
+		}
+	}
+
+	slog.Info(fmt.Sprintf("Updating message in channel '%s' at timestamp '%s'", msg.Channel, msg.TS))
+
+	apiStart := time.Now()
+	channelID, timestamp, _, err := slackClient.UpdateMessage(msg.Channel, msg.TS, msgOptions...)
+	slackAPILatencySeconds.WithLabelValues("chat.update").Observe(time.Since(apiStart).Seconds())
+	if err != nil {
+		if rl != nil {
+			rl.throttleIfRateLimited("chat.update", msg.Channel, err)
+		}
+		return "", "", fmt.Errorf("error updating message on Slack: %w", err)
+	}
+
+	slog.Info(fmt.Sprintf("Message updated successfully in channel %s (timestamp: %s)", channelID, timestamp))
+	return channelID, timestamp, nil
+}
+
+// deleteSlackMessage deletes a previously posted message and logs the
+// outcome. It is the fire-and-forget entry point used by the Redis-backed
+// processDeletes loop, where there is no caller waiting on the result.
+func deleteSlackMessage(ctx context.Context, slackClient *slack.Client, msg DeleteMessage, rl *RateLimiter) {
+	if err := deleteSlackMessageWithResponse(ctx, slackClient, msg, rl); err != nil {
+		slog.Error(fmt.Sprintf("Error deleting message: %v", err))
+	}
+}
+
+// deleteSlackMessageWithResponse deletes a previously posted message and
+// reports whether it succeeded, so callers that need to act on failure (HTTP
+// handlers) can do so. rl throttles the call to respect Slack's per-channel
+// rate limits and backs off further on a 429 response.
+func deleteSlackMessageWithResponse(ctx context.Context, slackClient *slack.Client, msg DeleteMessage, rl *RateLimiter) error {
+	if msg.Channel == "" || msg.TS == "" {
+		return fmt.Errorf("%w: channel and ts are required", ErrInvalidMessage)
+	}
+
+	if rl != nil {
+		if err := rl.Wait(ctx, "chat.delete", msg.Channel); err != nil {
+			return fmt.Errorf("rate limit wait interrupted: %w", err)
+		}
+	}
+
+	slog.Info(fmt.Sprintf("Deleting message in channel '%s' at timestamp '%s'", msg.Channel, msg.TS))
+
+	apiStart := time.Now()
+	_, _, err := slackClient.DeleteMessage(msg.Channel, msg.TS)
+	slackAPILatencySeconds.WithLabelValues("chat.delete").Observe(time.Since(apiStart).Seconds())
+	if err != nil {
+		if rl != nil {
+			rl.throttleIfRateLimited("chat.delete", msg.Channel, err)
+		}
+		return fmt.Errorf("error deleting message on Slack: %w", err)
+	}
+
+	slog.Info(fmt.Sprintf("Message deleted successfully in channel %s (timestamp: %s)", msg.Channel, msg.TS))
+	return nil
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestUpdateSlackMessageWithResponseValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  UpdateMessage
+	}{
+		{name: "missing channel", msg: UpdateMessage{TS: "1234.5678", Text: "updated"}},
+		{name: "missing ts", msg: UpdateMessage{Channel: "C1", Text: "updated"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := updateSlackMessageWithResponse(nil, nil, tt.msg, nil)
+			if !errors.Is(err, ErrInvalidMessage) {
+				t.Errorf("updateSlackMessageWithResponse() error = %v, want wrapped ErrInvalidMessage", err)
+			}
+		})
+	}
+}
+
+func TestDeleteSlackMessageWithResponseValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  DeleteMessage
+	}{
+		{name: "missing channel", msg: DeleteMessage{TS: "1234.5678"}},
+		{name: "missing ts", msg: DeleteMessage{Channel: "C1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := deleteSlackMessageWithResponse(nil, nil, tt.msg, nil)
+			if !errors.Is(err, ErrInvalidMessage) {
+				t.Errorf("deleteSlackMessageWithResponse() error = %v, want wrapped ErrInvalidMessage", err)
+			}
+		})
+	}
+}